@@ -1,14 +1,24 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"jokes-provider/config"
 	"jokes-provider/helpers"
+	"jokes-provider/metrics"
 	"jokes-provider/middleware"
+	"jokes-provider/models"
+	"jokes-provider/problems"
 	routes "jokes-provider/router"
 	"jokes-provider/services"
+	"jokes-provider/tracing"
+	"jokes-provider/utils"
+	"runtime/debug"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
 // Initialize sets up and returns a configured Fiber application
@@ -21,27 +31,101 @@ func Initialize() (*fiber.App, error) {
 		StrictRouting: config.FiberConfig.StrictRouting,
 		ServerHeader:  "Go Fiber - Jokes Provider",
 		AppName:       "Jokes Provider API",
+		// Every error, including unmatched routes and panics, becomes a problem details response
+		ErrorHandler: problems.Handler,
 	})
 
+	if err := config.InitTrustedProxies(); err != nil {
+		return nil, fmt.Errorf("trusted proxies configuration failed: %w", err)
+	}
+
 	config.InitializeLogger(app)
 	config.LogStartupInfo(config.AppConfig.Version, config.AppConfig.Flavor)
 
+	// Tracing starts early, so the initial dataset load is traced too
+	if err := initTracing(); err != nil {
+		return nil, err
+	}
+
 	if err := initRedis(); err != nil {
 		return nil, err
 	}
 
-	if err := initJokesData(); err != nil {
+	if err := initJokesLimits(); err != nil {
 		return nil, err
 	}
 
-	initMiddleware(app)
+	if err := initDailyTimezone(); err != nil {
+		return nil, err
+	}
+
+	source, err := initJokesData()
+	if err != nil {
+		return nil, err
+	}
+
+	initJokesWatcher(source)
+
+	if err := initAPIKeys(); err != nil {
+		return nil, err
+	}
+
+	if err := initJWT(); err != nil {
+		return nil, err
+	}
+
+	if err := initRateLimitPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := initMetrics(); err != nil {
+		return nil, err
+	}
+
+	if err := initMiddleware(app); err != nil {
+		return nil, err
+	}
 	routes.RegisterRoutes(app)
 
 	return app, nil
 }
 
-// initRedis initializes the Redis connection
+// initTracing sets up the OpenTelemetry exporter when tracing is enabled
+func initTracing() error {
+	if !config.AppConfig.TracingEnabled {
+		return nil
+	}
+
+	ratio, err := strconv.ParseFloat(config.AppConfig.TracingSampleRatio, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return fmt.Errorf("tracing configuration failed: TRACING_SAMPLE_RATIO must be between 0 and 1, got %q", config.AppConfig.TracingSampleRatio)
+	}
+
+	err = tracing.Init(tracing.Settings{
+		Exporter:       config.AppConfig.TracingExporter,
+		Endpoint:       config.AppConfig.TracingEndpoint,
+		SampleRatio:    ratio,
+		ServiceName:    config.AppConfig.TracingServiceName,
+		ServiceVersion: config.AppConfig.Version,
+	})
+	if err != nil {
+		config.LogError(nil, "Failed to initialize tracing", "exporter", config.AppConfig.TracingExporter, "error", err.Error())
+		return fmt.Errorf("tracing configuration failed: %w", err)
+	}
+
+	config.LogInfo(nil, "Tracing enabled", "exporter", config.AppConfig.TracingExporter, "sample_ratio", ratio)
+	return nil
+}
+
+// initRedis initializes the Redis connection; an unreachable Redis only degrades the service
 func initRedis() error {
+	switch config.CacheConfig.CacheReadiness {
+	case utils.CacheReadinessRequired, utils.CacheReadinessOptional:
+	default:
+		return fmt.Errorf("redis initialization failed: CACHE_READINESS must be %q or %q, got %q",
+			utils.CacheReadinessRequired, utils.CacheReadinessOptional, config.CacheConfig.CacheReadiness)
+	}
+
 	if err := middleware.InitRedis(); err != nil {
 		config.LogError(nil, "Failed to initialize Redis connection", "error", err.Error())
 		return fmt.Errorf("redis initialization failed: %w", err)
@@ -49,18 +133,154 @@ func initRedis() error {
 	return nil
 }
 
-// initJokesData loads jokes from CSV file
-func initJokesData() error {
-	if err := helpers.LoadJokesFromCSV(nil, config.AppConfig.JokesFilePath); err != nil {
+// initJokesLimits checks the request size limits, which size allocations per request
+func initJokesLimits() error {
+	if config.AppConfig.RandomMaxCount < 1 {
+		return fmt.Errorf("jokes limits configuration failed: JOKES_RANDOM_MAX_COUNT must be a positive integer, got %d", config.AppConfig.RandomMaxCount)
+	}
+	if config.AppConfig.BatchMaxIDs < 1 {
+		return fmt.Errorf("jokes limits configuration failed: JOKES_BATCH_MAX_IDS must be a positive integer, got %d", config.AppConfig.BatchMaxIDs)
+	}
+	return nil
+}
+
+// initDailyTimezone checks the default time zone of the joke of the day
+func initDailyTimezone() error {
+	if _, err := helpers.LoadDailyLocation(config.AppConfig.DailyTimezone); err != nil {
+		return fmt.Errorf("daily joke configuration failed: DAILY_TIMEZONE must be an IANA time zone or UTC, got %q", config.AppConfig.DailyTimezone)
+	}
+	return nil
+}
+
+// initJokesData loads jokes from the configured source into the in-memory repository
+func initJokesData() (helpers.JokeSource, error) {
+	source, err := helpers.NewJokeSource(config.AppConfig.JokesSource, config.AppConfig.JokesFilePath, config.AppConfig.JokesSQLiteTable)
+	if err != nil {
+		config.LogError(nil, "Invalid jokes source", "source", config.AppConfig.JokesSource, "error", err.Error())
+		return nil, fmt.Errorf("jokes source configuration failed: %w", err)
+	}
+
+	if err := helpers.LoadJokes(nil, source); err != nil {
 		config.LogError(nil, "Failed to load jokes data", "error", err.Error())
-		return fmt.Errorf("jokes data loading failed: %w", err)
+		return nil, fmt.Errorf("jokes data loading failed: %w", err)
+	}
+	return source, nil
+}
+
+// initJokesWatcher starts hot reloading of the jokes source when enabled
+func initJokesWatcher(source helpers.JokeSource) {
+	if !config.AppConfig.JokesReloadEnabled {
+		config.LogInfo(nil, "Jokes file watcher is disabled")
+		return
+	}
+
+	interval := utils.GetDurationFromEnv(config.AppConfig.JokesReloadInterval, 30*time.Second)
+	helpers.StartJokesWatcher(source, interval)
+}
+
+// initAPIKeys loads the API keys when API key authentication is enabled
+func initAPIKeys() error {
+	if !config.AppConfig.APIKeysEnabled {
+		return nil
+	}
+
+	if err := helpers.LoadAPIKeys(config.AppConfig.APIKeysFile, config.AppConfig.APIKeys); err != nil {
+		config.LogError(nil, "Failed to load API keys", "error", err.Error())
+		return fmt.Errorf("API keys loading failed: %w", err)
+	}
+	return nil
+}
+
+// initJWT prepares bearer token validation when JWT authentication is enabled
+func initJWT() error {
+	if !config.AppConfig.JWTEnabled {
+		return nil
+	}
+
+	err := helpers.ConfigureJWT(helpers.JWTSettings{
+		Algorithms: helpers.SplitList(config.AppConfig.JWTAlgorithms),
+		Secret:     config.AppConfig.JWTSecret,
+		JWKSFile:   config.AppConfig.JWTJWKSFile,
+		Issuer:     config.AppConfig.JWTIssuer,
+		Audience:   config.AppConfig.JWTAudience,
+		Leeway:     utils.GetDurationFromEnv(config.AppConfig.JWTLeeway, 30*time.Second),
+		ScopeClaim: config.AppConfig.JWTScopeClaim,
+	})
+	if err != nil {
+		config.LogError(nil, "Failed to configure JWT authentication", "error", err.Error())
+		return fmt.Errorf("JWT configuration failed: %w", err)
+	}
+	return nil
+}
+
+// initRateLimitPolicy loads the rate limit policies, with RATE_LIMIT_* as the default policy
+func initRateLimitPolicy() error {
+	if !config.AppConfig.RateLimitEnabled {
+		return nil
+	}
+
+	err := helpers.LoadRateLimitPolicy(config.AppConfig.RateLimitPolicyFile, models.RateLimitPolicy{
+		Name:        utils.RateLimitDefaultPolicy,
+		MaxRequests: config.AppConfig.RateLimitMaxRequests,
+		Window:      utils.GetDurationFromEnv(config.AppConfig.RateLimitDuration, 1*time.Minute).String(),
+		Algorithm:   config.AppConfig.RateLimitAlgorithm,
+		Burst:       config.AppConfig.RateLimitBurst,
+	})
+	if err != nil {
+		config.LogError(nil, "Failed to load rate limit policy", "error", err.Error())
+		return fmt.Errorf("rate limit policy loading failed: %w", err)
+	}
+	return nil
+}
+
+// initMetrics starts the separate metrics server when METRICS_PORT is set
+func initMetrics() error {
+	if !config.AppConfig.MetricsEnabled || config.AppConfig.MetricsPort == "" {
+		return nil
+	}
+
+	if config.FiberConfig.Prefork {
+		return fmt.Errorf("metrics configuration failed: METRICS_PORT cannot be used with FIBER_PREFORK")
+	}
+
+	if err := services.StartMetricsServer(); err != nil {
+		config.LogError(nil, "Failed to start metrics server", "port", config.AppConfig.MetricsPort, "error", err.Error())
+		return fmt.Errorf("metrics server failed: %w", err)
 	}
 	return nil
 }
 
 // initMiddleware sets up all middleware
-func initMiddleware(app *fiber.App) {
-	app.Use(services.SetupRateLimiter())
+func initMiddleware(app *fiber.App) error {
+	rateLimiter, err := services.SetupRateLimiter()
+	if err != nil {
+		config.LogError(nil, "Invalid rate limiter configuration", "algorithm", config.AppConfig.RateLimitAlgorithm,
+			"max_requests", config.AppConfig.RateLimitMaxRequests, "error", err.Error())
+		return fmt.Errorf("rate limiter configuration failed: %w", err)
+	}
+
+	// The server span covers every other middleware
+	if config.AppConfig.TracingEnabled {
+		app.Use(tracing.Middleware())
+	}
+
+	// Metrics wrap everything else, so rejected requests are counted too
+	if config.AppConfig.MetricsEnabled {
+		app.Use(metrics.Middleware(config.AppConfig.MetricsPath))
+	}
+
+	// Recovered inside the tracing and metrics middleware, so panics count as failed requests
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
+			config.LogError(c, "Recovered from panic", "panic", fmt.Sprint(e), "stack", string(debug.Stack()))
+		},
+	}))
+
+	// Rate limiting runs first, so requests with wrong credentials are throttled too
+	app.Use(rateLimiter)
+	app.Use(services.SetupAuth())
+	return nil
 }
 
 // Start starts the Fiber application server
@@ -68,11 +288,38 @@ func Start(app *fiber.App) error {
 	return app.Listen(":" + config.AppConfig.Port)
 }
 
-// Shutdown gracefully shuts down the application
-func Shutdown() error {
-	if err := middleware.CloseRedis(); err != nil {
+// Shutdown gracefully shuts down the application. The readiness probe fails
+// for SHUTDOWN_GRACE_PERIOD before in-flight requests get SHUTDOWN_TIMEOUT to finish.
+func Shutdown(app *fiber.App) error {
+	services.MarkShuttingDown()
+
+	gracePeriod := utils.GetDurationFromEnv(config.AppConfig.ShutdownGracePeriod, 5*time.Second)
+	timeout := utils.GetDurationFromEnv(config.AppConfig.ShutdownTimeout, 10*time.Second)
+	config.LogInfo(nil, "Shutting down", "grace_period", gracePeriod.String(), "timeout", timeout.String())
+	time.Sleep(gracePeriod)
+
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		config.LogError(nil, "Error draining connections", "error", err.Error())
+	}
+
+	helpers.StopJokesWatcher()
+
+	if err := services.StopMetricsServer(); err != nil {
+		config.LogError(nil, "Error stopping metrics server", "error", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracing.Shutdown(ctx); err != nil {
+		config.LogError(nil, "Error flushing traces", "error", err.Error())
+	}
+
+	err := middleware.CloseRedis()
+	if err != nil {
 		config.LogError(nil, "Error closing Redis", "error", err.Error())
-		return err
 	}
-	return nil
+
+	config.LogInfo(nil, "Shutdown complete")
+	config.FlushLogs()
+	return err
 }
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"jokes-provider/utils"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// trustedProxies are the networks whose forwarding headers are believed
+var trustedProxies []netip.Prefix
+
+// InitTrustedProxies parses the TRUSTED_PROXIES networks; "none" trusts no proxy
+func InitTrustedProxies() error {
+	if strings.EqualFold(strings.TrimSpace(AppConfig.TrustedProxies), utils.TrustedProxiesNone) {
+		trustedProxies = nil
+		return nil
+	}
+
+	networks, err := ParseNetworks(strings.Split(AppConfig.TrustedProxies, ","))
+	if err != nil {
+		return err
+	}
+	trustedProxies = networks
+	return nil
+}
+
+// ParseNetworks parses CIDR prefixes and bare addresses, skipping empty values
+func ParseNetworks(values []string) ([]netip.Prefix, error) {
+	networks := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(value); err == nil {
+			addr = addr.Unmap()
+			networks = append(networks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		network, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", value)
+		}
+		networks = append(networks, network.Masked())
+	}
+	return networks, nil
+}
+
+// InNetworks reports whether ip belongs to any of the networks
+func InNetworks(networks []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	return inNetworks(networks, addr)
+}
+
+func inNetworks(networks []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent the request. Behind a
+// trusted proxy, IP_HEADER_NAME is walked from the right to the first untrusted hop.
+func ClientIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(utils.LocalsClientIP).(string); ok {
+		return ip
+	}
+
+	ip := resolveClientIP(c)
+	c.Locals(utils.LocalsClientIP, ip)
+	return ip
+}
+
+func resolveClientIP(c *fiber.Ctx) string {
+	remote, ok := netip.AddrFromSlice(c.Context().RemoteIP())
+	if !ok {
+		return c.IP()
+	}
+	remote = remote.Unmap()
+	if !inNetworks(trustedProxies, remote) {
+		return remote.String()
+	}
+
+	header := c.Get(AppConfig.IPHeaderName)
+	if header == "" {
+		return remote.String()
+	}
+
+	var hops []string
+	if strings.EqualFold(AppConfig.IPHeaderName, fiber.HeaderForwarded) {
+		hops = forwardedFor(header)
+	} else {
+		hops = strings.Split(header, ",")
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseHop(hops[i])
+		if !ok {
+			// Nothing left of a malformed hop can be trusted
+			break
+		}
+		client = addr
+		if !inNetworks(trustedProxies, addr) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+// forwardedFor returns the for= parameters of an RFC 7239 Forwarded header
+func forwardedFor(header string) []string {
+	var hops []string
+	for _, element := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(key, "for") {
+				hops = append(hops, value)
+			}
+		}
+	}
+	return hops
+}
+
+// parseHop parses one forwarded address, which may be quoted and carry a port
+func parseHop(hop string) (netip.Addr, bool) {
+	hop = strings.Trim(strings.TrimSpace(hop), `"`)
+	if addr, err := netip.ParseAddr(strings.Trim(hop, "[]")); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	return netip.Addr{}, false
+}
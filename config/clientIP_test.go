@@ -0,0 +1,126 @@
+package config
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// resolveWith resolves the client IP of a request that reached the service
+// from remote with header set to value
+func resolveWith(t *testing.T, trusted, header, remote, value string) string {
+	t.Helper()
+
+	AppConfig.TrustedProxies = trusted
+	AppConfig.IPHeaderName = header
+	if err := InitTrustedProxies(); err != nil {
+		t.Fatalf("InitTrustedProxies(%q) error: %v", trusted, err)
+	}
+
+	var req fasthttp.Request
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(remote), Port: 40000}, nil)
+
+	app := fiber.New()
+	c := app.AcquireCtx(fctx)
+	defer app.ReleaseCtx(c)
+	return ClientIP(c)
+}
+
+func TestClientIP(t *testing.T) {
+	LoadEnvVars()
+	const (
+		private = "10.0.0.0/8,fc00::/7"
+		xff     = fiber.HeaderXForwardedFor
+		fwd     = fiber.HeaderForwarded
+	)
+
+	tests := []struct {
+		name    string
+		trusted string
+		header  string
+		remote  string
+		value   string
+		want    string
+	}{
+		// Trusted and untrusted hops
+		{"no header", private, xff, "10.0.0.1", "", "10.0.0.1"},
+		{"untrusted peer ignores header", private, xff, "203.0.113.9", "198.51.100.1", "203.0.113.9"},
+		{"no trusted proxies ignores header", "none", xff, "10.0.0.1", "198.51.100.1", "10.0.0.1"},
+		{"single trusted hop", private, xff, "10.0.0.1", "198.51.100.1", "198.51.100.1"},
+		{"chain of trusted proxies", private, xff, "10.0.0.1", "198.51.100.1, 10.0.0.3, 10.0.0.2", "198.51.100.1"},
+		{"only trusted hops", private, xff, "10.0.0.1", "10.0.0.3, 10.0.0.2", "10.0.0.3"},
+		{"stops at first untrusted hop", private, xff, "10.0.0.1", "198.51.100.1, 203.0.113.7, 10.0.0.2", "203.0.113.7"},
+		{"single host network", "192.0.2.10", xff, "192.0.2.10", "198.51.100.1", "198.51.100.1"},
+		{"ipv4 mapped peer", private, xff, "::ffff:10.0.0.1", "198.51.100.1", "198.51.100.1"},
+
+		// Spoofed left-most entries
+		{"spoofed left-most entry", private, xff, "10.0.0.1", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"spoofed trusted address", private, xff, "10.0.0.1", "10.9.9.9, 198.51.100.1", "198.51.100.1"},
+		{"spoofed forwarded element", private, fwd, "10.0.0.1", "for=1.2.3.4, for=198.51.100.1", "198.51.100.1"},
+
+		// Malformed hops
+		{"malformed only hop", private, xff, "10.0.0.1", "not-an-ip", "10.0.0.1"},
+		{"malformed hop behind proxy", private, xff, "10.0.0.1", "198.51.100.1, garbage, 10.0.0.2", "10.0.0.2"},
+		{"empty hop", private, xff, "10.0.0.1", "198.51.100.1, , 10.0.0.2", "10.0.0.2"},
+		{"unknown forwarded hop", private, fwd, "10.0.0.1", "for=198.51.100.1, for=unknown", "10.0.0.1"},
+		{"obfuscated forwarded hop", private, fwd, "10.0.0.1", "for=_hidden", "10.0.0.1"},
+		{"forwarded without for", private, fwd, "10.0.0.1", "proto=https;by=10.0.0.1", "10.0.0.1"},
+
+		// IPv6 in brackets and with ports
+		{"ipv6 hop", private, xff, "fc00::1", "2001:db8::1", "2001:db8::1"},
+		{"ipv6 peer ignored when untrusted", private, xff, "2001:db8::9", "2001:db8::1", "2001:db8::9"},
+		{"ipv6 in brackets", private, xff, "10.0.0.1", "[2001:db8::1]", "2001:db8::1"},
+		{"ipv6 in brackets with port", private, xff, "10.0.0.1", "[2001:db8::1]:443", "2001:db8::1"},
+		{"ipv4 with port", private, xff, "10.0.0.1", "198.51.100.1:8080", "198.51.100.1"},
+		{"trusted ipv6 hop with port", private, xff, "10.0.0.1", "198.51.100.1, [fc00::2]:80", "198.51.100.1"},
+
+		// Quoted for= values
+		{"forwarded plain", private, fwd, "10.0.0.1", "for=198.51.100.1", "198.51.100.1"},
+		{"forwarded quoted ipv6 with port", private, fwd, "10.0.0.1", `for="[2001:db8::1]:4711"`, "2001:db8::1"},
+		{"forwarded quoted ipv4", private, fwd, "10.0.0.1", `for="198.51.100.1"`, "198.51.100.1"},
+		{"forwarded case and parameters", private, fwd, "10.0.0.1", "proto=https;For=198.51.100.1;by=10.0.0.1", "198.51.100.1"},
+		{"forwarded chain", private, fwd, "10.0.0.1", `for=198.51.100.1;proto=https, for="[fc00::2]:80"`, "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveWith(t, tt.trusted, tt.header, tt.remote, tt.value)
+			if got != tt.want {
+				t.Errorf("ClientIP(%s: %q from %s) = %q, want %q", tt.header, tt.value, tt.remote, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{" 10.0.0.0/8", "", "192.0.2.10", "::ffff:192.0.2.20", "fc00::1/7"})
+	if err != nil {
+		t.Fatalf("ParseNetworks error: %v", err)
+	}
+
+	for ip, want := range map[string]bool{
+		"10.1.2.3":   true,
+		"192.0.2.10": true,
+		"192.0.2.11": false,
+		"192.0.2.20": true,
+		"fd00::1":    true,
+		"2001:db8::": false,
+		"not-an-ip":  false,
+	} {
+		if got := InNetworks(networks, ip); got != want {
+			t.Errorf("InNetworks(%q) = %v, want %v", ip, got, want)
+		}
+	}
+
+	for _, value := range []string{"10.0.0.0/33", "example.com"} {
+		if _, err := ParseNetworks([]string{value}); err == nil {
+			t.Errorf("ParseNetworks(%q) succeeded, want error", value)
+		}
+	}
+}
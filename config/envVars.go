@@ -16,6 +16,10 @@ func LoadEnvVars() {
 		Port:        utils.GetEnv("PORT", "3000"),
 		Environment: utils.GetEnv("ENVIRONMENT", "development"),
 
+		// Graceful shutdown
+		ShutdownGracePeriod: utils.GetEnv("SHUTDOWN_GRACE_PERIOD", "5s"),
+		ShutdownTimeout:     utils.GetEnv("SHUTDOWN_TIMEOUT", "10s"),
+
 		// Logging
 		LogLevel:         utils.GetEnv("LOG_LEVEL", "info"),
 		LogFormat:        utils.GetEnv("LOG_FORMAT", "[${ip}]:${port} ${status} - ${method} ${path}"),
@@ -25,16 +29,76 @@ func LoadEnvVars() {
 		// Build information (loaded from environment, set by Docker build args)
 		Version: utils.GetEnv("BUILD_VERSION", "dev"),
 		Flavor:  utils.GetEnv("BUILD_FLAVOR", "development"),
-		// File paths
-		JokesFilePath: utils.GetEnv("JOKES_FILE_PATH", "/data/jokes.csv"),
+		// Jokes data source
+		JokesSource:      utils.GetEnv("JOKES_SOURCE", utils.JokesSourceCSV),
+		JokesFilePath:    utils.GetEnv("JOKES_FILE_PATH", "/data/jokes.csv"),
+		JokesSQLiteTable: utils.GetEnv("JOKES_SQLITE_TABLE", "jokes"),
+		// Jokes column mapping
+		JokesColumnID:       utils.GetEnv("JOKES_COLUMN_ID", utils.CSVColumnID),
+		JokesColumnJoke:     utils.GetEnv("JOKES_COLUMN_JOKE", utils.CSVColumnJoke),
+		JokesColumnCategory: utils.GetEnv("JOKES_COLUMN_CATEGORY", utils.CSVColumnCategory),
+		// Jokes dataset reload
+		JokesReloadEnabled:  utils.GetEnv("JOKES_RELOAD_ENABLED", "true") == "true",
+		JokesReloadInterval: utils.GetEnv("JOKES_RELOAD_INTERVAL", "30s"),
+		// Jokes API limits
+		RandomMaxCount: utils.ParseInt(utils.GetEnv("JOKES_RANDOM_MAX_COUNT", "20")),
+		BatchMaxIDs:    utils.ParseInt(utils.GetEnv("JOKES_BATCH_MAX_IDS", "100")),
+		// Random selection
+		RandomMode:      utils.GetEnv("RANDOM_MODE", utils.RandomModeUniform),
+		RandomStickyKey: utils.GetEnv("RANDOM_STICKY_KEY", utils.RandomStickyKeyClient),
+		// Joke of the day
+		DailyTimezone: utils.GetEnv("DAILY_TIMEZONE", "UTC"),
+		DailySeed:     utils.GetEnv("DAILY_SEED", "jokes-provider"),
 		// Request headers
 		IPHeaderName:      utils.GetEnv("IP_HEADER_NAME", "X-Forwarded-For"),
+		TrustedProxies:    utils.GetEnv("TRUSTED_PROXIES", "127.0.0.0/8,::1"),
 		CountryHeaderName: utils.GetEnv("COUNTRY_HEADER_NAME", "X-Country-Name"),
+		SessionHeaderName: utils.GetEnv("SESSION_HEADER_NAME", "X-Session-ID"),
+		// Sessions
+		SessionCookieName: utils.GetEnv("SESSION_COOKIE_NAME", "session_id"),
+		SessionTTL:        utils.GetEnv("SESSION_TTL", "30m"),
+
+		// API keys
+		APIKeysEnabled:   utils.GetEnv("API_KEYS_ENABLED", "false") == "true",
+		APIKeysFile:      utils.GetEnv("API_KEYS_FILE", ""),
+		APIKeys:          utils.GetEnv("API_KEYS", ""),
+		APIKeyHeaderName: utils.GetEnv("API_KEY_HEADER_NAME", "X-API-Key"),
+		// JWT bearer tokens
+		JWTEnabled:    utils.GetEnv("JWT_ENABLED", "false") == "true",
+		JWTAlgorithms: utils.GetEnv("JWT_ALGORITHMS", "HS256,RS256,ES256"),
+		JWTSecret:     utils.GetEnv("JWT_SECRET", ""),
+		JWTJWKSFile:   utils.GetEnv("JWT_JWKS_FILE", ""),
+		JWTIssuer:     utils.GetEnv("JWT_ISSUER", ""),
+		JWTAudience:   utils.GetEnv("JWT_AUDIENCE", ""),
+		JWTLeeway:     utils.GetEnv("JWT_LEEWAY", "30s"),
+		JWTScopeClaim: utils.GetEnv("JWT_SCOPE_CLAIM", "scope"),
+		// Routes open without an API key or token (API_KEYS_ANONYMOUS_ROUTES is the former name)
+		AnonymousRoutes: utils.GetEnv("AUTH_ANONYMOUS_ROUTES", utils.GetEnv("API_KEYS_ANONYMOUS_ROUTES", "/v1/jokes/random,/v1/jokes/daily,/health/*,/swagger*,/docs/*")),
+
+		// Admin API
+		AdminUsername:  utils.GetEnv("ADMIN_USERNAME", ""),
+		AdminPassword:  utils.GetEnv("ADMIN_PASSWORD", ""),
+		AdminImportMax: utils.ParseInt(utils.GetEnv("ADMIN_IMPORT_MAX_JOKES", "1000")),
 
 		// Rate limiter configuration
 		RateLimitEnabled:     utils.GetEnv("RATE_LIMIT_ENABLED", "false") == "true",
 		RateLimitMaxRequests: utils.ParseInt(utils.GetEnv("RATE_LIMIT_MAX_REQUESTS", "100")),
 		RateLimitDuration:    utils.GetEnv("RATE_LIMITER_EXPIRATION", "1m"),
+		RateLimitAlgorithm:   utils.GetEnv("RATE_LIMIT_ALGORITHM", utils.RateLimitFixedWindow),
+		RateLimitBurst:       utils.ParseInt(utils.GetEnv("RATE_LIMIT_BURST", "0")),
+		RateLimitPolicyFile:  utils.GetEnv("RATE_LIMIT_POLICY_FILE", ""),
+
+		// Prometheus metrics
+		MetricsEnabled: utils.GetEnv("METRICS_ENABLED", "false") == "true",
+		MetricsPath:    utils.GetEnv("METRICS_PATH", "/metrics"),
+		MetricsPort:    utils.GetEnv("METRICS_PORT", ""),
+
+		// OpenTelemetry tracing
+		TracingEnabled:     utils.GetEnv("TRACING_ENABLED", "false") == "true",
+		TracingExporter:    utils.GetEnv("TRACING_EXPORTER", utils.TracingExporterOTLPHTTP),
+		TracingEndpoint:    utils.GetEnv("TRACING_ENDPOINT", ""),
+		TracingSampleRatio: utils.GetEnv("TRACING_SAMPLE_RATIO", "1"),
+		TracingServiceName: utils.GetEnv("TRACING_SERVICE_NAME", "jokes-provider"),
 	}
 
 	// Cache configuration
@@ -45,6 +109,10 @@ func LoadEnvVars() {
 		CacheCaCertPath:     utils.GetEnv("CACHE_CA_CERT", ""),
 		CacheClientCertPath: utils.GetEnv("CACHE_CLIENT_CERT", ""),
 		CacheClientKeyPath:  utils.GetEnv("CACHE_CLIENT_KEY", ""),
+
+		CacheBreakerThreshold:     utils.ParseInt(utils.GetEnv("CACHE_BREAKER_THRESHOLD", "5")),
+		CacheBreakerProbeInterval: utils.GetEnv("CACHE_BREAKER_PROBE_INTERVAL", "10s"),
+		CacheReadiness:            utils.GetEnv("CACHE_READINESS", utils.CacheReadinessRequired),
 	}
 
 	// Fiber configuration
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -56,3 +61,140 @@ func ReadCSVWithHeaders(c *fiber.Ctx, filePath string) ([]map[string]string, err
 
 	return result, nil
 }
+
+// ReadJSONLines reads a JSON Lines file with one object per non-empty line
+func ReadJSONLines(c *fiber.Ctx, filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		LogError(c, "Failed to open JSON Lines file", "file_path", filePath, "error", err.Error())
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []map[string]string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row, err := decodeJSONLine(data)
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			LogError(c, "Failed to read JSON Lines file", "file_path", filePath, "error", err.Error())
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		LogError(c, "Failed to read JSON Lines file", "file_path", filePath, "error", err.Error())
+		return nil, err
+	}
+
+	LogInfo(c, "JSON Lines file read successfully", "file_path", filePath, "records", len(rows))
+	return rows, nil
+}
+
+// decodeJSONLine decodes one JSON object with scalar values into a string map
+func decodeJSONLine(data []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(object))
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+			row[key] = ""
+		case string:
+			row[key] = v
+		case json.Number:
+			row[key] = v.String()
+		case bool:
+			row[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("field %q must be a string, number or boolean", key)
+		}
+	}
+
+	return row, nil
+}
+
+// WriteCSVAtomic replaces the CSV file with records through a renamed temporary file
+func WriteCSVAtomic(c *fiber.Ctx, filePath string, records [][]string) error {
+	return writeFileAtomic(c, filePath, func(file *os.File) error {
+		writer := csv.NewWriter(file)
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+		return writer.Error()
+	})
+}
+
+// WriteJSONLinesAtomic replaces the JSON Lines file with one object per row, like WriteCSVAtomic
+func WriteJSONLinesAtomic(c *fiber.Ctx, filePath string, rows []map[string]string) error {
+	return writeFileAtomic(c, filePath, func(file *os.File) error {
+		encoder := json.NewEncoder(file)
+		encoder.SetEscapeHTML(false)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func writeFileAtomic(c *fiber.Ctx, filePath string, write func(file *os.File) error) error {
+	// Hidden temporary name, so directory sources never pick it up
+	file, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		LogError(c, "Failed to create temporary file", "file_path", filePath, "error", err.Error())
+		return err
+	}
+	tempPath := file.Name()
+
+	fail := func(err error) error {
+		file.Close()
+		os.Remove(tempPath)
+		LogError(c, "Failed to write file", "file_path", filePath, "error", err.Error())
+		return err
+	}
+
+	if err := write(file); err != nil {
+		return fail(err)
+	}
+	if err := file.Sync(); err != nil {
+		return fail(err)
+	}
+
+	// Keep the permissions of the file being replaced
+	if info, err := os.Stat(filePath); err == nil {
+		if err := file.Chmod(info.Mode().Perm()); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tempPath)
+		LogError(c, "Failed to write file", "file_path", filePath, "error", err.Error())
+		return err
+	}
+
+	if err := os.Rename(tempPath, filePath); err != nil {
+		os.Remove(tempPath)
+		LogError(c, "Failed to replace file", "file_path", filePath, "error", err.Error())
+		return err
+	}
+
+	LogInfo(c, "File written successfully", "file_path", filePath)
+	return nil
+}
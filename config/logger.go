@@ -12,6 +12,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ContextLogger struct {
@@ -37,11 +38,7 @@ func (cl *ContextLogger) LogWithContext(c *fiber.Ctx, level string, message stri
 	}
 
 	if c != nil {
-		if forwardedFor := c.Get(AppConfig.IPHeaderName); forwardedFor != "" {
-			entry.IPAddress = forwardedFor
-		} else {
-			entry.IPAddress = c.IP()
-		}
+		entry.IPAddress = ClientIP(c)
 
 		if country := c.Get(AppConfig.CountryHeaderName); country != "" {
 			entry.Country = country
@@ -50,6 +47,19 @@ func (cl *ContextLogger) LogWithContext(c *fiber.Ctx, level string, message stri
 		if requestID := c.Get(fiber.HeaderXRequestID); requestID != "" {
 			entry.RequestID = requestID
 		}
+
+		if apiKey, ok := c.Locals(utils.LocalsAPIKeyName).(string); ok {
+			entry.APIKey = apiKey
+		}
+
+		if subject, ok := c.Locals(utils.LocalsSubject).(string); ok {
+			entry.Subject = subject
+		}
+
+		if span := trace.SpanContextFromContext(c.UserContext()); span.IsValid() {
+			entry.TraceID = span.TraceID().String()
+			entry.SpanID = span.SpanID().String()
+		}
 	}
 
 	if cl.ContextLogger.Format == "json" {
@@ -77,6 +87,16 @@ func (cl *ContextLogger) logJSON(entry *models.LogEntry, fields ...interface{})
 	if entry.Country != "" {
 		logMap["country"] = entry.Country
 	}
+	if entry.APIKey != "" {
+		logMap["api_key"] = entry.APIKey
+	}
+	if entry.Subject != "" {
+		logMap["subject"] = entry.Subject
+	}
+	if entry.TraceID != "" {
+		logMap["trace_id"] = entry.TraceID
+		logMap["span_id"] = entry.SpanID
+	}
 
 	// Add custom fields (key, value, key, value...)
 	for i := 0; i < len(fields); i += 2 {
@@ -110,6 +130,15 @@ func (cl *ContextLogger) printTextFormat(entry *models.LogEntry, fields ...inter
 		if AppConfig.Version != "" && AppConfig.Flavor != "" {
 			logMsg += fmt.Sprintf(" version=%s-%s", AppConfig.Flavor, AppConfig.Version)
 		}
+		if entry.APIKey != "" {
+			logMsg += fmt.Sprintf(" api_key=%s", entry.APIKey)
+		}
+		if entry.Subject != "" {
+			logMsg += fmt.Sprintf(" subject=%s", entry.Subject)
+		}
+		if entry.TraceID != "" {
+			logMsg += fmt.Sprintf(" trace_id=%s span_id=%s", entry.TraceID, entry.SpanID)
+		}
 		for i := 0; i < len(fields); i += 2 {
 			if i+1 < len(fields) {
 				key := fmt.Sprintf("%v", fields[i])
@@ -122,6 +151,13 @@ func (cl *ContextLogger) printTextFormat(entry *models.LogEntry, fields ...inter
 	cl.ContextLogger.Logger.Println(logMsg)
 }
 
+// accessLogTags makes the access log report the same client IP as the context logger
+var accessLogTags = map[string]logger.LogFunc{
+	logger.TagIP: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+		return output.WriteString(ClientIP(c))
+	},
+}
+
 func InitializeLogger(app *fiber.App) {
 	app.Use(requestid.New())
 
@@ -130,13 +166,15 @@ func InitializeLogger(app *fiber.App) {
 
 	if logFormat == "json" {
 		app.Use(logger.New(logger.Config{
-			Format: "${time_rfc3339} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
-			Output: os.Stdout,
+			Format:     "${time_rfc3339} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
+			Output:     os.Stdout,
+			CustomTags: accessLogTags,
 		}))
 	} else {
 		app.Use(logger.New(logger.Config{
-			Format: "[${time_rfc3339}] ${status} ${latency} ${ip} ${method} ${path}${error}\n",
-			Output: os.Stdout,
+			Format:     "[${time_rfc3339}] ${status} ${latency} ${ip} ${method} ${path}${error}\n",
+			Output:     os.Stdout,
+			CustomTags: accessLogTags,
 		}))
 	}
 }
@@ -153,6 +191,12 @@ func LogDebug(c *fiber.Ctx, message string, fields ...interface{}) {
 	contextLogger.LogWithContext(c, "DEBUG", message, fields...)
 }
 
+// FlushLogs commits the log lines written so far to the output
+func FlushLogs() {
+	_ = os.Stdout.Sync()
+	_ = os.Stderr.Sync()
+}
+
 func LogStartupInfo(version, flavor string) {
 	LogInfo(nil, "Application started", "version", version, "flavor", flavor, "environment", AppConfig.Environment, "port", AppConfig.Port)
 }
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"database/sql"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	_ "modernc.org/sqlite"
+)
+
+// ReadSQLiteTable reads every row of a table, header row first like ReadCSV.
+// The caller validates the table name.
+func ReadSQLiteTable(c *fiber.Ctx, filePath string, table string) ([][]string, error) {
+	dsn := "file:" + (&url.URL{Path: filePath}).EscapedPath() + "?mode=ro"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		LogError(c, "Failed to open SQLite database", "file_path", filePath, "error", err.Error())
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT * FROM "` + table + `"`)
+	if err != nil {
+		LogError(c, "Failed to query SQLite table", "file_path", filePath, "table", table, "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	records := [][]string{columns}
+	values := make([]sql.NullString, len(columns))
+	targets := make([]any, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			LogError(c, "Failed to read SQLite row", "file_path", filePath, "table", table, "error", err.Error())
+			return nil, err
+		}
+		record := make([]string, len(columns))
+		for i, value := range values {
+			record[i] = value.String
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		LogError(c, "Failed to read SQLite table", "file_path", filePath, "table", table, "error", err.Error())
+		return nil, err
+	}
+
+	LogInfo(c, "SQLite table read successfully", "file_path", filePath, "table", table, "records", len(records))
+	return records, nil
+}
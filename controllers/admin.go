@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"errors"
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/models"
+	"jokes-provider/problems"
+	"jokes-provider/services"
+	"jokes-provider/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminController handles the authenticated joke management endpoints
+type AdminController struct {
+	adminService *services.AdminService
+}
+
+// NewAdminController creates a new AdminController instance
+func NewAdminController() *AdminController {
+	return &AdminController{
+		adminService: services.NewAdminService(),
+	}
+}
+
+// CreateJoke godoc
+// @Summary      Create a joke
+// @Description  Adds a joke to the data source. The next numeric ID is assigned when id is absent. Attributes are matched to the data columns case-insensitively; unknown attributes become new columns.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BasicAuth
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Param        joke  body      models.Joke  true  "Joke, joke is required"
+// @Success      201  {object}  models.Joke  "Created joke"
+// @Header       201  {string}  Location  "URL of the created joke"
+// @Failure      400  {object}  models.Problem  "Invalid joke"
+// @Failure      401  {object}  models.Problem  "Unauthorized"
+// @Failure      403  {object}  models.Problem  "Token is missing the jokes:write scope"
+// @Failure      409  {object}  models.Problem  "Joke ID already exists or source is read-only"
+// @Failure      500  {object}  models.Problem  "Failed to persist jokes"
+// @Router       /admin/v1/jokes [post]
+func (ctrl *AdminController) CreateJoke(c *fiber.Ctx) error {
+	var joke models.Joke
+	if err := c.BodyParser(&joke); err != nil {
+		return invalidJokeBody()
+	}
+
+	created, err := ctrl.adminService.CreateJokes(c, []models.Joke{joke})
+	if err != nil {
+		return adminError(c, err)
+	}
+
+	c.Set(utils.HeaderLocation, utils.APIVersionV1+utils.RouteJokes+"/"+created[0].ID)
+
+	return c.Status(fiber.StatusCreated).JSON(created[0])
+}
+
+// ImportJokes godoc
+// @Summary      Import jokes in bulk
+// @Description  Adds several jokes at once, with the same rules as creating a single joke. The import is all or nothing: if any joke is invalid or reuses an existing ID, nothing is written.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BasicAuth
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Param        request  body      models.ImportRequest  true  "Jokes to import (capped by ADMIN_IMPORT_MAX_JOKES)"
+// @Success      201  {object}  models.ImportResponse  "Imported jokes with their IDs"
+// @Failure      400  {object}  models.Problem  "Invalid import request"
+// @Failure      401  {object}  models.Problem  "Unauthorized"
+// @Failure      403  {object}  models.Problem  "Token is missing the jokes:write scope"
+// @Failure      409  {object}  models.Problem  "Joke IDs already exist or source is read-only"
+// @Failure      500  {object}  models.Problem  "Failed to persist jokes"
+// @Router       /admin/v1/jokes/import [post]
+func (ctrl *AdminController) ImportJokes(c *fiber.Ctx) error {
+	var request models.ImportRequest
+	if err := c.BodyParser(&request); err != nil || len(request.Jokes) == 0 {
+		return problems.Validation(utils.ErrMsgInvalidImport)
+	}
+
+	if len(request.Jokes) > config.AppConfig.AdminImportMax {
+		return problems.Validation(utils.ErrMsgImportTooLarge).With(utils.JSONKeyMaxJokes, config.AppConfig.AdminImportMax)
+	}
+
+	created, err := ctrl.adminService.CreateJokes(c, request.Jokes)
+	if err != nil {
+		return adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.ImportResponse{
+		Imported: len(created),
+		Jokes:    created,
+	})
+}
+
+// UpdateJoke godoc
+// @Summary      Update a joke
+// @Description  Replaces all fields of an existing joke. The ID cannot be changed.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BasicAuth
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Param        id    path      string             true  "Joke ID"
+// @Param        joke  body      models.Joke  true  "Joke, joke is required"
+// @Success      200  {object}  models.Joke  "Updated joke"
+// @Failure      400  {object}  models.Problem  "Invalid joke"
+// @Failure      401  {object}  models.Problem  "Unauthorized"
+// @Failure      403  {object}  models.Problem  "Token is missing the jokes:write scope"
+// @Failure      404  {object}  models.Problem  "Joke not found"
+// @Failure      409  {object}  models.Problem  "Source is read-only"
+// @Failure      500  {object}  models.Problem  "Failed to persist jokes"
+// @Router       /admin/v1/jokes/{id} [put]
+func (ctrl *AdminController) UpdateJoke(c *fiber.Ctx) error {
+	// Route params point into a buffer Fiber reuses; the ID outlives the request
+	jokeID := strings.Clone(c.Params(utils.ParamID))
+
+	var joke models.Joke
+	if err := c.BodyParser(&joke); err != nil {
+		return invalidJokeBody()
+	}
+
+	updated, err := ctrl.adminService.UpdateJoke(c, jokeID, joke)
+	if err != nil {
+		return adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(updated)
+}
+
+// DeleteJoke godoc
+// @Summary      Delete a joke
+// @Description  Removes a joke from the data source. The last joke cannot be deleted.
+// @Tags         admin
+// @Security     BasicAuth
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Joke ID"
+// @Success      204  "Joke deleted"
+// @Failure      401  {object}  models.Problem  "Unauthorized"
+// @Failure      403  {object}  models.Problem  "Token is missing the jokes:write scope"
+// @Failure      404  {object}  models.Problem  "Joke not found"
+// @Failure      409  {object}  models.Problem  "Last joke or source is read-only"
+// @Failure      500  {object}  models.Problem  "Failed to persist jokes"
+// @Router       /admin/v1/jokes/{id} [delete]
+func (ctrl *AdminController) DeleteJoke(c *fiber.Ctx) error {
+	jokeID := c.Params(utils.ParamID)
+
+	if err := ctrl.adminService.DeleteJoke(c, jokeID); err != nil {
+		return adminError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func invalidJokeBody() error {
+	return problems.Validation(utils.ErrMsgInvalidJoke).With(utils.JSONKeyReason, "expected a JSON joke object with string fields")
+}
+
+// adminError maps joke write errors to problems
+func adminError(c *fiber.Ctx, err error) error {
+	var invalidErr *helpers.InvalidJokeError
+	var conflictErr *helpers.JokeConflictError
+	var skippedErr *helpers.SkippedRowsError
+
+	switch {
+	case errors.As(err, &invalidErr):
+		return problems.Validation(utils.ErrMsgInvalidJoke).With(utils.JSONKeyReason, invalidErr.Reason)
+	case errors.As(err, &conflictErr):
+		return problems.Conflict(utils.ErrMsgJokeExists).With(utils.JSONKeyIDs, conflictErr.IDs)
+	case errors.Is(err, helpers.ErrJokeNotFound):
+		return problems.NotFound(utils.ErrMsgJokeNotFound).With(utils.JSONKeyID, c.Params(utils.ParamID))
+	case errors.As(err, &skippedErr):
+		return problems.Conflict(utils.ErrMsgSourceHasSkippedRows).With(utils.JSONKeySkippedRows, skippedErr.Count)
+	case errors.Is(err, helpers.ErrSourceReadOnly):
+		return problems.Conflict(utils.ErrMsgSourceReadOnly)
+	case errors.Is(err, helpers.ErrNoJokesAvailable):
+		return problems.Conflict(utils.ErrMsgLastJoke)
+	}
+
+	return problems.Internal(utils.ErrMsgFailedToPersist, err)
+}
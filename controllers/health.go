@@ -21,7 +21,7 @@ func NewHealthController() *HealthController {
 
 // Readiness godoc
 // @Summary      Readiness check
-// @Description  Checks if the service is ready and dependencies are available (Redis, CSV file)
+// @Description  Checks if the service is ready and dependencies are available (Redis, CSV file). Fails once shutdown has started. With CACHE_READINESS=optional an unavailable Redis reports status degraded instead of failing.
 // @Tags         health
 // @Accept       json
 // @Produce      json
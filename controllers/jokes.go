@@ -1,11 +1,19 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"jokes-provider/config"
 	"jokes-provider/helpers"
+	"jokes-provider/models"
+	"jokes-provider/problems"
 	"jokes-provider/services"
 	"jokes-provider/utils"
-	"path"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -24,25 +32,186 @@ func NewJokeController() *JokeController {
 
 // GetRandomJoke godoc
 // @Summary      Get a random joke
-// @Description  Returns a random joke from the jokes database. Supports caching.
+// @Description  Returns a random joke from the jokes database, optionally restricted to one or more categories. With count, returns that many distinct jokes instead. Every pick is derived from a seed returned in X-Random-Seed; passing it back as seed replays the pick. A new seed is generated on every request unless RANDOM_MODE=sticky, which keeps one per client or session.
 // @Tags         jokes
 // @Accept       json
-// @Produce      json
-// @Success      200  {object}  models.Joke  "Random joke object with id and joke fields"
-// @Failure      500  {object}  map[string]string  "Failed to retrieve joke"
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        category  query     []string  false  "Category filter (repeat or comma-separate for several)"  collectionFormat(multi)
+// @Param        count     query     int       false  "Number of distinct jokes to return (capped by JOKES_RANDOM_MAX_COUNT)"
+// @Param        seed      query     string    false  "Seed making the pick reproducible for the same dataset version (generated when absent)"
+// @Param        X-Session-ID  header  string  false  "Session ID; the session never sees the same joke twice until all matching jokes were served (also read from the session_id cookie)"
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.Joke  "Random joke object with id and joke fields (models.RandomJokeList when count is set)"
+// @Header       200  {string}  X-Random-Seed  "Seed that reproduces this pick"
+// @Header       200  {string}  X-Dataset-Version  "Dataset version the seed applies to"
+// @Header       200  {int}  X-Session-Remaining  "Jokes this session has not seen yet (session requests only)"
+// @Failure      400  {object}  models.Problem  "Invalid count or seed"
+// @Failure      404  {object}  models.Problem  "Category not found"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Failure      500  {object}  models.Problem  "Failed to retrieve joke"
 // @Router       /v1/jokes/random [get]
 func (ctrl *JokeController) GetRandomJoke(c *fiber.Ctx) error {
-	cacheKey := path.Base(c.Path())
+	categories := queryValues(c, utils.QueryCategory)
 
-	joke, err := ctrl.jokeService.GetRandomJoke(c, cacheKey)
+	seed := c.Query(utils.QuerySeed)
+	if len(seed) > utils.MaxSeedLength {
+		return problems.Validation(utils.ErrMsgInvalidSeed)
+	}
+
+	sessionID := sessionIDFromRequest(c)
+	if len(sessionID) > utils.MaxSessionIDLength {
+		return problems.Validation(utils.ErrMsgInvalidSession)
+	}
+
+	// Every uniform pick is different, so shared caches must not store it
+	if !services.IsStickyRandom() {
+		c.Set(utils.HeaderCacheControl, utils.CacheControlNoStore)
+	}
+
+	// An explicit seed or sticky mode takes precedence over the session deck
+	if sessionID != "" && seed == "" && !services.IsStickyRandom() {
+		return ctrl.getSessionJokes(c, sessionID, categories)
+	}
+
+	if c.Query(utils.QueryCount) != "" {
+		return ctrl.getRandomJokes(c, seed, categories)
+	}
+
+	joke, selection, err := ctrl.jokeService.GetRandomJoke(c, seed, categories)
+	if err != nil {
+		return randomJokeError(err)
+	}
+
+	setRandomSelectionHeaders(c, selection)
+
+	return respond(c, fiber.StatusOK, "joke", joke)
+}
+
+// getRandomJokes handles GetRandomJoke when a count is requested
+func (ctrl *JokeController) getRandomJokes(c *fiber.Ctx, seed string, categories []string) error {
+	count, ok := randomCount(c)
+	if !ok {
+		return problems.Validation(utils.ErrMsgInvalidCount)
+	}
+
+	jokes, err := ctrl.jokeService.GetRandomJokes(c, seed, categories, count)
 	if err != nil {
-		config.LogError(c, "Error retrieving random joke", utils.JSONKeyError, err.Error())
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			utils.JSONKeyError: utils.ErrMsgFailedToRetrieve,
-		})
+		return randomJokeError(err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(joke)
+	setRandomSelectionHeaders(c, jokes.RandomSelection)
+
+	return respond(c, fiber.StatusOK, "jokes", jokes)
+}
+
+// getSessionJokes handles GetRandomJoke for a session, dealing jokes it has not seen yet
+func (ctrl *JokeController) getSessionJokes(c *fiber.Ctx, sessionID string, categories []string) error {
+	count := 1
+	if c.Query(utils.QueryCount) != "" {
+		var ok bool
+		if count, ok = randomCount(c); !ok {
+			return problems.Validation(utils.ErrMsgInvalidCount)
+		}
+	}
+
+	jokes, remaining, err := ctrl.jokeService.GetSessionJokes(c, sessionID, categories, count)
+	if err != nil {
+		return randomJokeError(err)
+	}
+
+	c.Set(utils.HeaderSessionLeft, strconv.Itoa(remaining))
+
+	if c.Query(utils.QueryCount) == "" {
+		return respond(c, fiber.StatusOK, "joke", jokes[0])
+	}
+
+	return respond(c, fiber.StatusOK, "jokes", models.RandomJokeList{
+		Data:  jokes,
+		Count: len(jokes),
+	})
+}
+
+// randomCount parses the count query parameter, capped at the configured maximum
+func randomCount(c *fiber.Ctx) (int, bool) {
+	count, ok := queryInt(c, utils.QueryCount, 0)
+	if !ok || count < 1 {
+		return 0, false
+	}
+	if count > config.AppConfig.RandomMaxCount {
+		count = config.AppConfig.RandomMaxCount
+	}
+	return count, true
+}
+
+// sessionIDFromRequest reads the session ID from the session header, falling back to the session cookie
+func sessionIDFromRequest(c *fiber.Ctx) string {
+	if sessionID := strings.TrimSpace(c.Get(config.AppConfig.SessionHeaderName)); sessionID != "" {
+		return sessionID
+	}
+	return strings.TrimSpace(c.Cookies(config.AppConfig.SessionCookieName))
+}
+
+// setRandomSelectionHeaders exposes the seed and dataset version that reproduce a random pick
+func setRandomSelectionHeaders(c *fiber.Ctx, selection models.RandomSelection) {
+	c.Set(utils.HeaderRandomSeed, selection.Seed)
+	c.Set(utils.HeaderDatasetVer, selection.DatasetVersion)
+}
+
+// randomJokeError maps random selection errors to problems
+func randomJokeError(err error) error {
+	var categoryErr *helpers.CategoryNotFoundError
+	if errors.As(err, &categoryErr) {
+		return problems.NotFound(utils.ErrMsgCategoryNotFound).
+			With(utils.JSONKeyCategory, strings.Join(categoryErr.Categories, ","))
+	}
+
+	return jokeReadError(err)
+}
+
+// jokeReadError maps dataset read errors to problems
+func jokeReadError(err error) error {
+	if errors.Is(err, helpers.ErrNoJokesAvailable) {
+		return problems.UpstreamUnavailable(utils.ErrMsgNoJokesAvailable, err)
+	}
+	return problems.Internal(utils.ErrMsgFailedToRetrieve, err)
+}
+
+// GetJokesBatch godoc
+// @Summary      Get several jokes by ID
+// @Description  Returns the jokes found for a list of IDs, in request order, and the IDs that were not found. Duplicated IDs are returned once.
+// @Tags         jokes
+// @Accept       json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        request  body      models.BatchRequest  true  "Joke IDs (capped by JOKES_BATCH_MAX_IDS)"
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.BatchResponse  "Found jokes and missing IDs"
+// @Failure      400  {object}  models.Problem  "Invalid batch request"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Router       /v1/jokes/batch [post]
+func (ctrl *JokeController) GetJokesBatch(c *fiber.Ctx) error {
+	var request models.BatchRequest
+	if err := c.BodyParser(&request); err != nil || len(request.IDs) == 0 {
+		return problems.Validation(utils.ErrMsgInvalidBatch)
+	}
+
+	jokeIDs := make([]string, 0, len(request.IDs))
+	seen := make(map[string]bool, len(request.IDs))
+	for _, jokeID := range request.IDs {
+		if jokeID = strings.TrimSpace(jokeID); jokeID != "" && !seen[jokeID] {
+			seen[jokeID] = true
+			jokeIDs = append(jokeIDs, jokeID)
+		}
+	}
+
+	if len(jokeIDs) == 0 {
+		return problems.Validation(utils.ErrMsgInvalidBatch)
+	}
+
+	if len(jokeIDs) > config.AppConfig.BatchMaxIDs {
+		return problems.Validation(utils.ErrMsgBatchTooLarge).With(utils.JSONKeyMaxIDs, config.AppConfig.BatchMaxIDs)
+	}
+
+	return respond(c, fiber.StatusOK, "batch", ctrl.jokeService.GetJokesBatch(c, jokeIDs))
 }
 
 // GetJokeByID godoc
@@ -50,35 +219,201 @@ func (ctrl *JokeController) GetRandomJoke(c *fiber.Ctx) error {
 // @Description  Returns a specific joke by its ID from the jokes database. Supports caching.
 // @Tags         jokes
 // @Accept       json
-// @Produce      json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
 // @Param        id   path      string  true  "Joke ID"
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
 // @Success      200  {object}  models.Joke  "Joke object with id and joke fields"
-// @Failure      400  {object}  map[string]string  "Joke ID is required"
-// @Failure      404  {object}  map[string]string  "Joke not found"
-// @Failure      500  {object}  map[string]string  "Failed to retrieve joke"
+// @Failure      400  {object}  models.Problem  "Joke ID is required"
+// @Failure      404  {object}  models.Problem  "Joke not found"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Failure      500  {object}  models.Problem  "Failed to retrieve joke"
 // @Router       /v1/jokes/{id} [get]
 func (ctrl *JokeController) GetJokeByID(c *fiber.Ctx) error {
 	jokeID := c.Params(utils.ParamID)
 
 	if jokeID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			utils.JSONKeyError: utils.ErrMsgJokeIDRequired,
-		})
+		return problems.Validation(utils.ErrMsgJokeIDRequired)
 	}
 
 	joke, err := ctrl.jokeService.GetJokeByID(c, jokeID)
 	if err != nil {
 		if err == helpers.ErrJokeNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				utils.JSONKeyError: utils.ErrMsgJokeNotFound,
-				utils.JSONKeyID:    jokeID,
-			})
+			return problems.NotFound(utils.ErrMsgJokeNotFound).With(utils.JSONKeyID, jokeID)
+		}
+		return problems.Internal(utils.ErrMsgFailedToRetrieve, err)
+	}
+
+	return respond(c, fiber.StatusOK, "joke", joke)
+}
+
+// GetCategories godoc
+// @Summary      List joke categories
+// @Description  Returns all joke categories with the number of jokes in each
+// @Tags         jokes
+// @Accept       json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.CategoryList  "Categories with joke counts"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Router       /v1/jokes/categories [get]
+func (ctrl *JokeController) GetCategories(c *fiber.Ctx) error {
+	return respond(c, fiber.StatusOK, "category_list", ctrl.jokeService.GetCategories(c))
+}
+
+// queryValues returns all values of a repeated or comma-separated query parameter
+func queryValues(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, raw := range c.Context().QueryArgs().PeekMulti(key) {
+		for _, value := range strings.Split(string(raw), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent
+// and false when it is not an integer
+func queryInt(c *fiber.Ctx, key string, def int) (int, bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return def, true
+	}
+	value, err := strconv.Atoi(raw)
+	return value, err == nil
+}
+
+// SearchJokes godoc
+// @Summary      Search jokes
+// @Description  Full-text search over joke text, ranked by relevance. Quote words for a phrase ("knock knock") and end a word with * for prefix matching (chick*).
+// @Tags         jokes
+// @Accept       json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        q       query     string  true   "Search query"
+// @Param        match   query     string  false  "Require all terms (all) or any term (any)"  Enums(all, any)  default(all)
+// @Param        limit   query     int     false  "Page size (max 50)"  default(10)
+// @Param        offset  query     int     false  "Number of results to skip"  default(0)
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.SearchResponse  "Ranked search results"
+// @Failure      400  {object}  models.Problem  "Invalid search parameters"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Router       /v1/jokes/search [get]
+func (ctrl *JokeController) SearchJokes(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query(utils.QuerySearch))
+	if query == "" {
+		return problems.Validation(utils.ErrMsgSearchRequired)
+	}
+
+	match := strings.ToLower(c.Query(utils.QueryMatch, utils.SearchMatchAll))
+	if match != utils.SearchMatchAll && match != utils.SearchMatchAny {
+		return problems.Validation(utils.ErrMsgInvalidMatchMode)
+	}
+
+	limit, limitOK := queryInt(c, utils.QueryLimit, utils.SearchDefaultLimit)
+	offset, offsetOK := queryInt(c, utils.QueryOffset, 0)
+	if !limitOK || !offsetOK || limit < 1 || offset < 0 {
+		return problems.Validation(utils.ErrMsgInvalidPaging)
+	}
+	if limit > utils.SearchMaxLimit {
+		limit = utils.SearchMaxLimit
+	}
+
+	response := ctrl.jokeService.SearchJokes(c, query, match == utils.SearchMatchAll, offset, limit)
+
+	return respond(c, fiber.StatusOK, "search", response)
+}
+
+// ListJokes godoc
+// @Summary      List all jokes
+// @Description  Returns jokes ordered by ID using cursor-based pagination. Follow next_cursor (or the Link header) until it is absent.
+// @Tags         jokes
+// @Accept       json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        limit   query     int     false  "Page size (max 100)"  default(20)
+// @Param        cursor  query     string  false  "Opaque cursor from a previous page"
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.JokeList  "One page of jokes"
+// @Header       200  {string}  Link  "Pagination links (rel=first, rel=next)"
+// @Header       200  {int}  X-Total-Count  "Total number of jokes"
+// @Failure      400  {object}  models.Problem  "Invalid pagination parameters"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Router       /v1/jokes [get]
+func (ctrl *JokeController) ListJokes(c *fiber.Ctx) error {
+	limit, ok := queryInt(c, utils.QueryLimit, utils.ListDefaultLimit)
+	if !ok || limit < 1 {
+		return problems.Validation(utils.ErrMsgInvalidPaging)
+	}
+	if limit > utils.ListMaxLimit {
+		limit = utils.ListMaxLimit
+	}
+
+	list, err := ctrl.jokeService.ListJokes(c, c.Query(utils.QueryCursor), limit)
+	if err != nil {
+		return problems.Validation(utils.ErrMsgInvalidCursor)
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, listPageURL(c, limit, ""))}
+	if list.NextCursor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, listPageURL(c, limit, list.NextCursor)))
+	}
+	c.Set(utils.HeaderLink, strings.Join(links, ", "))
+	c.Set(utils.HeaderTotalCount, strconv.Itoa(list.Total))
+
+	return respond(c, fiber.StatusOK, "jokes", list)
+}
+
+// listPageURL builds the absolute URL of a listing page in the requested format
+func listPageURL(c *fiber.Ctx, limit int, cursor string) string {
+	query := url.Values{}
+	query.Set(utils.QueryLimit, strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set(utils.QueryCursor, cursor)
+	}
+	if format := c.Query(utils.QueryFormat); format != "" {
+		query.Set(utils.QueryFormat, format)
+	}
+	return c.BaseURL() + c.Path() + "?" + query.Encode()
+}
+
+// GetDailyJoke godoc
+// @Summary      Get the joke of the day
+// @Description  Returns the same joke to everyone for a calendar day, selected deterministically from the dataset. Responses are cacheable until the next day boundary in the requested time zone.
+// @Tags         jokes
+// @Accept       json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        tz    query     string  false  "IANA time zone deciding which day is today (default DAILY_TIMEZONE)"
+// @Param        date  query     string  false  "Past day to look up, as YYYY-MM-DD (default today)"
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
+// @Success      200  {object}  models.DailyJoke  "Joke of the day"
+// @Header       200  {string}  Cache-Control  "public, max-age until the next day boundary"
+// @Header       200  {string}  Expires  "Next day boundary"
+// @Failure      400  {object}  models.Problem  "Invalid timezone or date"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
+// @Failure      500  {object}  models.Problem  "Failed to retrieve joke"
+// @Router       /v1/jokes/daily [get]
+func (ctrl *JokeController) GetDailyJoke(c *fiber.Ctx) error {
+	timezone := c.Query(utils.QueryTimezone, config.AppConfig.DailyTimezone)
+
+	daily, err := ctrl.jokeService.GetDailyJoke(c, timezone, c.Query(utils.QueryDate))
+	if err != nil {
+		switch err {
+		case helpers.ErrInvalidTimezone:
+			return problems.Validation(utils.ErrMsgInvalidTimezone)
+		case helpers.ErrInvalidDate:
+			return problems.Validation(utils.ErrMsgInvalidDate)
+		case helpers.ErrFutureDate:
+			return problems.Validation(utils.ErrMsgFutureDate)
 		}
-		config.LogError(c, "Error retrieving joke by ID", utils.JSONKeyID, jokeID, utils.JSONKeyError, err.Error())
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			utils.JSONKeyError: utils.ErrMsgFailedToRetrieve,
-		})
+		return jokeReadError(err)
+	}
+
+	maxAge := int(time.Until(daily.ExpiresAt).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
 	}
+	c.Set(utils.HeaderCacheControl, fmt.Sprintf("%s, max-age=%d", utils.CacheControlPublic, maxAge))
+	c.Set(utils.HeaderExpires, daily.ExpiresAt.UTC().Format(http.TimeFormat))
 
-	return c.Status(fiber.StatusOK).JSON(joke)
+	return respond(c, fiber.StatusOK, "daily_joke", daily)
 }
@@ -24,13 +24,15 @@ func NewMetadataController() *MetadataController {
 // @Description  Returns comprehensive application metadata including version, configuration, and environment information
 // @Tags         metadata
 // @Accept       json
-// @Produce      json
+// @Produce      json,plain,application/xml,application/yaml,text/csv,html
+// @Param        format  query     string  false  "Response format, overriding the Accept header"  Enums(json, text, xml, yaml, csv, html)
 // @Success      200  {object}  models.Metadata  "Application metadata"
+// @Failure      406  {object}  models.Problem  "None of the accepted formats is supported"
 // @Router       /api/v1/metadata [get]
 func (ctrl *MetadataController) GetMetadata(c *fiber.Ctx) error {
 	config.LogInfo(c, "Metadata requested")
 
 	metadata := ctrl.metadataService.GetMetadata()
 
-	return c.Status(fiber.StatusOK).JSON(metadata)
+	return respond(c, fiber.StatusOK, "metadata", metadata)
 }
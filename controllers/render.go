@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"fmt"
+	"jokes-provider/helpers"
+	"jokes-provider/problems"
+	"jokes-provider/services"
+	"jokes-provider/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// htmlTitle is the title of the HTML pages
+const htmlTitle = "Jokes Provider"
+
+// respond sends a successful response in the negotiated format, root naming the XML document element
+func respond(c *fiber.Ctx, status int, root string, v any) error {
+	var body []byte
+	var contentType string
+	var err error
+
+	switch services.ResponseFormat(c) {
+	case utils.FormatText:
+		body, err = helpers.RenderText(v)
+		contentType = fiber.MIMETextPlainCharsetUTF8
+	case utils.FormatXML:
+		body, err = helpers.RenderXML(root, v)
+		contentType = fiber.MIMEApplicationXMLCharsetUTF8
+	case utils.FormatYAML:
+		body, err = helpers.RenderYAML(v)
+		contentType = utils.MIMEApplicationYAML + "; charset=utf-8"
+	case utils.FormatCSV:
+		body, err = helpers.RenderCSV(v)
+		contentType = utils.MIMETextCSV + "; charset=utf-8"
+	case utils.FormatHTML:
+		body, err = helpers.RenderHTML(htmlTitle, v)
+		contentType = fiber.MIMETextHTMLCharsetUTF8
+	default:
+		return c.Status(status).JSON(v)
+	}
+
+	if err != nil {
+		return problems.Internal(utils.ErrMsgFailedToRetrieve, fmt.Errorf("rendering %s: %w", services.ResponseFormat(c), err))
+	}
+
+	c.Set(utils.HeaderContentType, contentType)
+	return c.Status(status).Send(body)
+}
@@ -24,6 +24,312 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/v1/jokes": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a joke to the data source. The next numeric ID is assigned when id is absent. Attributes are matched to the data columns case-insensitively; unknown attributes become new columns.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a joke",
+                "parameters": [
+                    {
+                        "description": "Joke, joke is required",
+                        "name": "joke",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Joke"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Joke"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created joke"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Token is missing the jokes:write scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Joke ID already exists or source is read-only",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist jokes",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/v1/jokes/import": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds several jokes at once, with the same rules as creating a single joke. The import is all or nothing: if any joke is invalid or reuses an existing ID, nothing is written.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import jokes in bulk",
+                "parameters": [
+                    {
+                        "description": "Jokes to import (capped by ADMIN_IMPORT_MAX_JOKES)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Imported jokes with their IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid import request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Token is missing the jokes:write scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Joke IDs already exist or source is read-only",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist jokes",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/v1/jokes/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces all fields of an existing joke. The ID cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a joke",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Joke ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Joke, joke is required",
+                        "name": "joke",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Joke"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Joke"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Token is missing the jokes:write scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Joke not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Source is read-only",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist jokes",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a joke from the data source. The last joke cannot be deleted.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a joke",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Joke ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Joke deleted"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Token is missing the jokes:write scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Joke not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Last joke or source is read-only",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to persist jokes",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/metadata": {
             "get": {
                 "description": "Returns comprehensive application metadata including version, configuration, and environment information",
@@ -31,18 +337,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
                 ],
                 "tags": [
                     "metadata"
                 ],
                 "summary": "Get application metadata",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Application metadata",
                         "schema": {
                             "$ref": "#/definitions/models.Metadata"
                         }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
@@ -69,7 +402,7 @@ const docTemplate = `{
         },
         "/health/readiness": {
             "get": {
-                "description": "Checks if the service is ready and dependencies are available (Redis, CSV file)",
+                "description": "Checks if the service is ready and dependencies are available (Redis, CSV file). Fails once shutdown has started. With CACHE_READINESS=optional an unavailable Redis reports status degraded instead of failing.",
                 "consumes": [
                     "application/json"
                 ],
@@ -96,33 +429,477 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/jokes/random": {
+        "/v1/jokes": {
+            "get": {
+                "description": "Returns jokes ordered by ID using cursor-based pagination. Follow next_cursor (or the Link header) until it is absent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "jokes"
+                ],
+                "summary": "List all jokes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of jokes",
+                        "schema": {
+                            "$ref": "#/definitions/models.JokeList"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Pagination links (rel=first, rel=next)"
+                            },
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "Total number of jokes"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jokes/batch": {
+            "post": {
+                "description": "Returns the jokes found for a list of IDs, in request order, and the IDs that were not found. Duplicated IDs are returned once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "jokes"
+                ],
+                "summary": "Get several jokes by ID",
+                "parameters": [
+                    {
+                        "description": "Joke IDs (capped by JOKES_BATCH_MAX_IDS)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Found jokes and missing IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid batch request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jokes/categories": {
             "get": {
-                "description": "Returns a random joke from the jokes database. Supports caching.",
+                "description": "Returns all joke categories with the number of jokes in each",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "jokes"
+                ],
+                "summary": "List joke categories",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Categories with joke counts",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryList"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jokes/daily": {
+            "get": {
+                "description": "Returns the same joke to everyone for a calendar day, selected deterministically from the dataset. Responses are cacheable until the next day boundary in the requested time zone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "jokes"
+                ],
+                "summary": "Get the joke of the day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "IANA time zone deciding which day is today (default DAILY_TIMEZONE)",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Past day to look up, as YYYY-MM-DD (default today)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Joke of the day",
+                        "schema": {
+                            "$ref": "#/definitions/models.DailyJoke"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age until the next day boundary"
+                            },
+                            "Expires": {
+                                "type": "string",
+                                "description": "Next day boundary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid timezone or date",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jokes/random": {
+            "get": {
+                "description": "Returns a random joke from the jokes database, optionally restricted to one or more categories. With count, returns that many distinct jokes instead. Every pick is derived from a seed returned in X-Random-Seed; passing it back as seed replays the pick. A new seed is generated on every request unless RANDOM_MODE=sticky, which keeps one per client or session.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
                 "tags": [
                     "jokes"
                 ],
                 "summary": "Get a random joke",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Category filter (repeat or comma-separate for several)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of distinct jokes to return (capped by JOKES_RANDOM_MAX_COUNT)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seed making the pick reproducible for the same dataset version (generated when absent)",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID; the session never sees the same joke twice until all matching jokes were served (also read from the session_id cookie)",
+                        "name": "X-Session-ID",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Random joke object with id and joke fields",
+                        "description": "Random joke object with id and joke fields (models.RandomJokeList when count is set)",
                         "schema": {
                             "$ref": "#/definitions/models.Joke"
+                        },
+                        "headers": {
+                            "X-Dataset-Version": {
+                                "type": "string",
+                                "description": "Dataset version the seed applies to"
+                            },
+                            "X-Random-Seed": {
+                                "type": "string",
+                                "description": "Seed that reproduces this pick"
+                            },
+                            "X-Session-Remaining": {
+                                "type": "int",
+                                "description": "Jokes this session has not seen yet (session requests only)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid count or seed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Failed to retrieve joke",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jokes/search": {
+            "get": {
+                "description": "Full-text search over joke text, ranked by relevance. Quote words for a phrase (\"knock knock\") and end a word with * for prefix matching (chick*).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "jokes"
+                ],
+                "summary": "Search jokes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "any"
+                        ],
+                        "type": "string",
+                        "default": "all",
+                        "description": "Require all terms (all) or any term (any)",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked search results",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -135,7 +912,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "application/xml",
+                    "application/yaml",
+                    "text/csv",
+                    "text/html"
                 ],
                 "tags": [
                     "jokes"
@@ -148,67 +930,173 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text",
+                            "xml",
+                            "yaml",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Joke object with id and joke fields",
+                        "schema": {
+                            "$ref": "#/definitions/models.Joke"
+                        }
+                    },
+                    "400": {
+                        "description": "Joke ID is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Joke not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted formats is supported",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve joke",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AppInfo": {
+            "type": "object",
+            "properties": {
+                "flavor": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthInfo": {
+            "type": "object",
+            "properties": {
+                "admin_enabled": {
+                    "type": "boolean"
+                },
+                "anonymous_routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "api_key_count": {
+                    "type": "integer"
+                },
+                "api_key_header_name": {
+                    "type": "string"
+                },
+                "api_keys_enabled": {
+                    "type": "boolean"
+                },
+                "jwt_algorithms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "jwt_audience": {
+                    "type": "string"
+                },
+                "jwt_enabled": {
+                    "type": "boolean"
+                },
+                "jwt_issuer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Joke object with id and joke fields",
-                        "schema": {
-                            "$ref": "#/definitions/models.Joke"
-                        }
-                    },
-                    "400": {
-                        "description": "Joke ID is required",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Joke not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to retrieve joke",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                }
+            }
+        },
+        "models.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "jokes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Joke"
+                    }
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AppInfo": {
+        },
+        "models.CacheBreakerInfo": {
             "type": "object",
             "properties": {
-                "flavor": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "last_error": {
                     "type": "string"
                 },
-                "name": {
+                "opened_at": {
                     "type": "string"
                 },
-                "version": {
+                "probe_interval": {
+                    "type": "string"
+                },
+                "state": {
                     "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
                 }
             }
         },
         "models.CacheInfo": {
             "type": "object",
             "properties": {
+                "breaker": {
+                    "$ref": "#/definitions/models.CacheBreakerInfo"
+                },
                 "enabled": {
                     "type": "boolean"
                 },
+                "readiness": {
+                    "type": "string"
+                },
                 "ttl": {
                     "type": "string"
                 },
@@ -217,6 +1105,86 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryList": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DailyJoke": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "joke": {
+                    "$ref": "#/definitions/models.Joke"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DatasetInfo": {
+            "type": "object",
+            "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "joke_count": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "last_reload_at": {
+                    "type": "string"
+                },
+                "loaded_at": {
+                    "type": "string"
+                },
+                "reload_count": {
+                    "type": "integer"
+                },
+                "reload_enabled": {
+                    "type": "boolean"
+                },
+                "reload_interval": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
         "models.FiberInfo": {
             "type": "object",
             "properties": {
@@ -247,12 +1215,55 @@ const docTemplate = `{
                 },
                 "ip_header_name": {
                     "type": "string"
+                },
+                "session_header_name": {
+                    "type": "string"
+                },
+                "trusted_proxies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ImportRequest": {
+            "type": "object",
+            "properties": {
+                "jokes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Joke"
+                    }
+                }
+            }
+        },
+        "models.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                },
+                "jokes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Joke"
+                    }
                 }
             }
         },
         "models.Joke": {
             "type": "object",
             "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -261,6 +1272,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.JokeList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Joke"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.LoggingInfo": {
             "type": "object",
             "properties": {
@@ -284,9 +1315,15 @@ const docTemplate = `{
                 "app": {
                     "$ref": "#/definitions/models.AppInfo"
                 },
+                "auth": {
+                    "$ref": "#/definitions/models.AuthInfo"
+                },
                 "cache": {
                     "$ref": "#/definitions/models.CacheInfo"
                 },
+                "dataset": {
+                    "$ref": "#/definitions/models.DatasetInfo"
+                },
                 "fiber": {
                     "$ref": "#/definitions/models.FiberInfo"
                 },
@@ -299,25 +1336,165 @@ const docTemplate = `{
                 "logging": {
                     "$ref": "#/definitions/models.LoggingInfo"
                 },
+                "metrics": {
+                    "$ref": "#/definitions/models.MetricsInfo"
+                },
+                "random": {
+                    "$ref": "#/definitions/models.RandomInfo"
+                },
                 "rate_limiter": {
                     "$ref": "#/definitions/models.RateLimiterInfo"
                 },
                 "server": {
                     "$ref": "#/definitions/models.ServerInfo"
+                },
+                "tracing": {
+                    "$ref": "#/definitions/models.TracingInfo"
+                }
+            }
+        },
+        "models.MetricsInfo": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "Joke not found"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/v1/jokes/42"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f6c2a8e-5d1b-4e7a-9c0f-2b8d7e6a1c4f"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/not-found"
+                }
+            }
+        },
+        "models.RandomInfo": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "sticky_key": {
+                    "type": "string"
+                },
+                "sticky_ttl": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RateLimitPolicy": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "burst": {
+                    "type": "integer"
+                },
+                "exempt": {
+                    "type": "boolean"
+                },
+                "max_requests": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "window": {
+                    "type": "string"
                 }
             }
         },
         "models.RateLimiterInfo": {
             "type": "object",
             "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "burst": {
+                    "type": "integer"
+                },
                 "duration": {
                     "type": "string"
                 },
                 "enabled": {
                     "type": "boolean"
                 },
+                "exempt_networks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exempt_routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "internal_networks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "max_requests": {
                     "type": "integer"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RateLimitPolicy"
+                    }
+                },
+                "policy_file": {
+                    "type": "string"
+                },
+                "storage": {
+                    "type": "string"
                 }
             }
         },
@@ -335,6 +1512,44 @@ const docTemplate = `{
                 },
                 "redis": {
                     "type": "string"
+                },
+                "status": {
+                    "description": "Status is ready, degraded (serving without Redis) or not_ready",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "joke": {
+                    "$ref": "#/definitions/models.Joke"
+                },
+                "score": {
+                    "type": "number"
                 }
             }
         },
@@ -351,11 +1566,42 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.TracingInfo": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "exporter": {
+                    "type": "string"
+                },
+                "sample_ratio": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BasicAuth": {
             "type": "basic"
+        },
+        "BearerAuth": {
+            "description": "JWT bearer token, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     },
     "externalDocs": {
@@ -0,0 +1,121 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jokes-provider/config"
+	"jokes-provider/models"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidAPIKeys is returned when the API key configuration cannot be used
+var ErrInvalidAPIKeys = errors.New("invalid API key configuration")
+
+// apiKeys indexes the configured keys by their SHA-256
+var apiKeys = map[[sha256.Size]byte]*models.APIKey{}
+
+// LoadAPIKeys loads keys from a JSON file and a list of name:key[:rate_limit] entries
+func LoadAPIKeys(filePath, inline string) error {
+	var keys []models.APIKey
+
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAPIKeys, err)
+		}
+		if err := json.Unmarshal(data, &keys); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidAPIKeys, filePath, err)
+		}
+	}
+
+	for _, entry := range SplitList(inline) {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return fmt.Errorf("%w: expected name:key[:rate_limit], got %q", ErrInvalidAPIKeys, parts[0]+":...")
+		}
+		key := models.APIKey{Name: parts[0], Key: parts[1]}
+		if len(parts) == 3 {
+			limit, err := strconv.Atoi(parts[2])
+			if err != nil || limit < 0 {
+				return fmt.Errorf("%w: invalid rate limit for key %q", ErrInvalidAPIKeys, key.Name)
+			}
+			key.RateLimit = limit
+		}
+		keys = append(keys, key)
+	}
+
+	loaded := make(map[[sha256.Size]byte]*models.APIKey, len(keys))
+	names := make(map[string]bool, len(keys))
+	for i := range keys {
+		key := &keys[i]
+		key.Name = strings.TrimSpace(key.Name)
+		if key.Name == "" || key.Key == "" {
+			return fmt.Errorf("%w: every key needs a name and a key", ErrInvalidAPIKeys)
+		}
+		if names[key.Name] {
+			return fmt.Errorf("%w: duplicate key name %q", ErrInvalidAPIKeys, key.Name)
+		}
+		hash := sha256.Sum256([]byte(key.Key))
+		if _, exists := loaded[hash]; exists {
+			return fmt.Errorf("%w: key %q reuses another key's secret", ErrInvalidAPIKeys, key.Name)
+		}
+		names[key.Name] = true
+		loaded[hash] = key
+	}
+
+	apiKeys = loaded
+	return nil
+}
+
+// FindAPIKey returns the configured key matching the presented secret
+func FindAPIKey(secret string) (*models.APIKey, bool) {
+	key, ok := apiKeys[sha256.Sum256([]byte(secret))]
+	return key, ok
+}
+
+// GetAPIKeys returns all configured keys
+func GetAPIKeys() []*models.APIKey {
+	keys := make([]*models.APIKey, 0, len(apiKeys))
+	for _, key := range apiKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MatchRoute reports whether path matches any pattern; a trailing '*' matches a prefix
+func MatchRoute(patterns []string, path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	if !config.FiberConfig.CaseSensitive {
+		path = strings.ToLower(path)
+	}
+	for _, pattern := range patterns {
+		if !config.FiberConfig.CaseSensitive {
+			pattern = strings.ToLower(pattern)
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) || path == strings.TrimSuffix(prefix, "/") {
+				return true
+			}
+			continue
+		}
+		if strings.TrimSuffix(pattern, "/") == path {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitList splits a comma-separated configuration value, dropping empty entries
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,14 +2,25 @@ package helpers
 
 import (
 	"jokes-provider/config"
+	"jokes-provider/metrics"
 	"jokes-provider/middleware"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// CheckRedisStatus checks if Redis is accessible
+// CheckRedisStatus checks if Redis is accessible, without calling it while the circuit breaker is open
 func CheckRedisStatus(c *fiber.Ctx) bool {
+	up := checkRedisStatus(c)
+	metrics.ObserveRedisHealth(up)
+	return up
+}
+
+func checkRedisStatus(c *fiber.Ctx) bool {
+	if !middleware.IsRedisAvailable() {
+		config.LogInfo(c, "Redis health check skipped: circuit breaker open or not connected")
+		return false
+	}
 	store := middleware.GetRedisStore()
 
 	// Try to set and get a test key
@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// cursorPrefix versions the cursor format so it can evolve without breaking clients
+const cursorPrefix = "v1:"
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor builds an opaque pagination cursor pointing after the given joke ID
+func EncodeCursor(jokeID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + jokeID))
+}
+
+// DecodeCursor returns the joke ID an opaque cursor points after
+func DecodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+
+	value := string(decoded)
+	if !strings.HasPrefix(value, cursorPrefix) || len(value) == len(cursorPrefix) {
+		return "", ErrInvalidCursor
+	}
+
+	return strings.TrimPrefix(value, cursorPrefix), nil
+}
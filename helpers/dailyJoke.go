@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DailyDateLayout is the calendar date format accepted and returned by the daily endpoint
+const DailyDateLayout = "2006-01-02"
+
+var (
+	// ErrInvalidTimezone is returned when the tz parameter is not an IANA time zone
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrInvalidDate is returned when the date parameter is not YYYY-MM-DD
+	ErrInvalidDate = errors.New("invalid date")
+	// ErrFutureDate is returned when asking for a day that has not started yet
+	ErrFutureDate = errors.New("date is in the future")
+)
+
+// LoadDailyLocation loads an IANA time zone; "Local" would differ between replicas
+func LoadDailyLocation(timezone string) (*time.Location, error) {
+	if strings.EqualFold(strings.TrimSpace(timezone), "Local") {
+		return nil, ErrInvalidTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return location, nil
+}
+
+// ResolveDailyDate returns midnight of the requested day in timezone, today when empty
+func ResolveDailyDate(timezone, date string, now time.Time) (time.Time, error) {
+	location, err := LoadDailyLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := now.In(location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	if date == "" {
+		return today, nil
+	}
+
+	day, err := time.ParseInLocation(DailyDateLayout, date, location)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	if day.After(today) {
+		return time.Time{}, ErrFutureDate
+	}
+
+	return day, nil
+}
+
+// NextDayBoundary returns the next midnight after now in the given day's time zone
+func NextDayBoundary(day time.Time, now time.Time) time.Time {
+	local := now.In(day.Location())
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, day.Location())
+}
+
+// DailyKey builds the hash key selecting the joke of a calendar day in any time zone
+func DailyKey(seed string, day time.Time) string {
+	return seed + ":daily:" + day.Format(DailyDateLayout)
+}
@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolveDailyDate(t *testing.T) {
+	// 23:30 UTC is already the next day in Tokyo
+	now := time.Date(2026, 3, 14, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		date     string
+		want     string
+		err      error
+	}{
+		{"UTC", "", "2026-03-14", nil},
+		{"Asia/Tokyo", "", "2026-03-15", nil},
+		{"America/New_York", "", "2026-03-14", nil},
+		{"UTC", "2026-03-01", "2026-03-01", nil},
+		{"UTC", "2026-03-15", "", ErrFutureDate},
+		{"Asia/Tokyo", "2026-03-15", "2026-03-15", nil},
+		{"UTC", "14/03/2026", "", ErrInvalidDate},
+		{"Local", "", "", ErrInvalidTimezone},
+		{"local", "", "", ErrInvalidTimezone},
+		{" Local ", "", "", ErrInvalidTimezone},
+		{"Mars/Olympus", "", "", ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone+" "+tt.date, func(t *testing.T) {
+			day, err := ResolveDailyDate(tt.timezone, tt.date, now)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ResolveDailyDate error = %v, want %v", err, tt.err)
+			}
+			if err == nil && day.Format(DailyDateLayout) != tt.want {
+				t.Errorf("ResolveDailyDate = %s, want %s", day.Format(DailyDateLayout), tt.want)
+			}
+		})
+	}
+}
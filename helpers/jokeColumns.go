@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/models"
+	"strings"
+)
+
+// jokeColumns names the source columns holding the joke fields
+type jokeColumns struct {
+	id       string
+	joke     string
+	category string
+}
+
+// configuredColumns returns the column mapping set by JOKES_COLUMN_*
+func configuredColumns() jokeColumns {
+	return jokeColumns{
+		id:       config.AppConfig.JokesColumnID,
+		joke:     config.AppConfig.JokesColumnJoke,
+		category: config.AppConfig.JokesColumnCategory,
+	}
+}
+
+// names lists the mapped columns in record order
+func (cols jokeColumns) names() []string {
+	return []string{cols.id, cols.joke, cols.category}
+}
+
+// isMapped reports whether a column holds one of the joke fields
+func (cols jokeColumns) isMapped(column string) bool {
+	for _, name := range cols.names() {
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the field of joke stored in column
+func (cols jokeColumns) value(joke models.Joke, column string) string {
+	switch {
+	case strings.EqualFold(column, cols.id):
+		return joke.ID
+	case strings.EqualFold(column, cols.joke):
+		return joke.Joke
+	case strings.EqualFold(column, cols.category):
+		return joke.Category
+	}
+	return joke.Attributes[column]
+}
+
+// columnIndex returns the position of column in headers, ignoring case, or -1
+func columnIndex(headers []string, column string) int {
+	for i, header := range headers {
+		if strings.EqualFold(header, column) {
+			return i
+		}
+	}
+	return -1
+}
+
+// jokeFromRecord maps one record onto a joke, other columns becoming attributes
+func jokeFromRecord(cols jokeColumns, headers, record []string) models.Joke {
+	var joke models.Joke
+	for i, header := range headers {
+		if i >= len(record) {
+			break
+		}
+
+		switch {
+		case strings.EqualFold(header, cols.id):
+			joke.ID = record[i]
+		case strings.EqualFold(header, cols.joke):
+			joke.Joke = record[i]
+		case strings.EqualFold(header, cols.category):
+			joke.Category = record[i]
+		case record[i] != "":
+			if joke.Attributes == nil {
+				joke.Attributes = make(map[string]string)
+			}
+			joke.Attributes[header] = record[i]
+		}
+	}
+	return joke
+}
+
+// recordsFromJokes turns jokes back into header-first records
+func recordsFromJokes(cols jokeColumns, headers []string, jokes []models.Joke) [][]string {
+	records := make([][]string, 0, len(jokes)+1)
+	records = append(records, headers)
+	for _, joke := range jokes {
+		record := make([]string, len(headers))
+		for i, header := range headers {
+			record[i] = cols.value(joke, header)
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,520 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrJokeNotFound is returned when a joke with the specified ID is not found
+var ErrJokeNotFound = errors.New("joke not found")
+
+// ErrNoJokesAvailable is returned when the dataset is empty or not loaded yet
+var ErrNoJokesAvailable = errors.New(utils.ErrMsgNoJokesAvailable)
+
+// CategoryNotFoundError is returned when a requested category does not exist
+type CategoryNotFoundError struct {
+	Categories []string
+}
+
+func (e *CategoryNotFoundError) Error() string {
+	return "category not found: " + strings.Join(e.Categories, ",")
+}
+
+// JokeRepository provides read access to the jokes dataset
+type JokeRepository interface {
+	GetRandom(seed string, categories []string) (models.Joke, string, error)
+	GetRandomSample(seed string, categories []string, count int) ([]models.Joke, string, error)
+	DrawFromDeck(deck *models.SessionDeck, categories []string, count int, newSeed func() string) ([]models.Joke, int, error)
+	GetDeterministic(key string) (models.Joke, error)
+	GetByID(jokeID string) (models.Joke, error)
+	GetByIDs(jokeIDs []string) ([]models.Joke, []string)
+	GetCategories() []models.Category
+	Search(query string, matchAll bool, offset, limit int) ([]models.SearchResult, int)
+	List(afterID string, limit int) ([]models.Joke, string)
+	Count() int
+	Version() string
+}
+
+// jokeDataset is an immutable, indexed snapshot of the jokes data
+type jokeDataset struct {
+	headers    []string
+	columns    jokeColumns
+	jokes      []models.Joke
+	byID       map[string]int
+	byCategory map[string][]int
+	categories []models.Category
+	search     *searchIndex
+	byIDOrder  []int
+	version    string
+	// skipped counts rows without an ID and repeated IDs
+	skipped int
+}
+
+// compareIDs orders numeric IDs by value before the others
+func compareIDs(a, b string) int {
+	aNum, aErr := strconv.ParseInt(a, 10, 64)
+	bNum, bErr := strconv.ParseInt(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil && aNum != bNum:
+		if aNum < bNum {
+			return -1
+		}
+		return 1
+	case aErr == nil && bErr != nil:
+		return -1
+	case aErr != nil && bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// datasetVersion fingerprints the raw records
+func datasetVersion(records [][]string) string {
+	hash := fnv.New64a()
+	for _, record := range records {
+		for _, field := range record {
+			hash.Write([]byte(field))
+			hash.Write([]byte{0x1f})
+		}
+		hash.Write([]byte{0x1e})
+	}
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// jokePool is the whole dataset or the jokes of one or more categories
+type jokePool struct {
+	segments [][]int
+	size     int
+}
+
+// at maps a position in the pool to an index in the dataset
+func (p jokePool) at(position int) int {
+	if p.segments == nil {
+		return position
+	}
+	for _, indices := range p.segments {
+		if position < len(indices) {
+			return indices[position]
+		}
+		position -= len(indices)
+	}
+	return -1
+}
+
+// pool resolves a category filter; no categories means the whole dataset
+func (d *jokeDataset) pool(categories []string) (jokePool, error) {
+	if len(categories) == 0 {
+		return jokePool{size: len(d.jokes)}, nil
+	}
+
+	var unknown []string
+	pool := jokePool{segments: [][]int{}}
+	seen := make(map[string]bool, len(categories))
+
+	for _, category := range categories {
+		key := normalizeCategory(category)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		indices, ok := d.byCategory[key]
+		if !ok {
+			unknown = append(unknown, category)
+			continue
+		}
+		pool.segments = append(pool.segments, indices)
+		pool.size += len(indices)
+	}
+
+	if len(unknown) > 0 {
+		return jokePool{}, &CategoryNotFoundError{Categories: unknown}
+	}
+
+	return pool, nil
+}
+
+// normalizeCategory makes category lookups case and whitespace insensitive
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// CategoryKey joins categories into a cache key part independent of their case and order
+func CategoryKey(categories []string) string {
+	keys := make([]string, len(categories))
+	for i, category := range categories {
+		keys[i] = normalizeCategory(category)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// newJokeDataset builds an indexed dataset from raw records, header row first
+func newJokeDataset(records [][]string) (*jokeDataset, error) {
+	if len(records) < 2 {
+		return nil, ErrNoJokesAvailable
+	}
+
+	headers := records[0]
+	columns := configuredColumns()
+
+	idIndex := columnIndex(headers, columns.id)
+	if idIndex == -1 {
+		return nil, fmt.Errorf("%s: %q", utils.ErrMsgIDColumnNotFound, columns.id)
+	}
+
+	dataset := &jokeDataset{
+		headers:    headers,
+		columns:    columns,
+		jokes:      make([]models.Joke, 0, len(records)-1),
+		byID:       make(map[string]int, len(records)-1),
+		byCategory: make(map[string][]int),
+	}
+	categoryNames := make(map[string]string)
+	var texts []string
+
+	for _, row := range records[1:] {
+		if idIndex >= len(row) {
+			dataset.skipped++
+			continue
+		}
+
+		// First occurrence wins for duplicated IDs
+		if _, exists := dataset.byID[row[idIndex]]; exists {
+			dataset.skipped++
+			continue
+		}
+
+		joke := jokeFromRecord(columns, headers, row)
+
+		if key := normalizeCategory(joke.Category); key != "" {
+			dataset.byCategory[key] = append(dataset.byCategory[key], len(dataset.jokes))
+			if _, seen := categoryNames[key]; !seen {
+				categoryNames[key] = strings.TrimSpace(joke.Category)
+			}
+		}
+
+		texts = append(texts, joke.Joke)
+
+		dataset.byID[joke.ID] = len(dataset.jokes)
+		dataset.jokes = append(dataset.jokes, joke)
+	}
+
+	if len(dataset.jokes) == 0 {
+		return nil, ErrNoJokesAvailable
+	}
+
+	for key, indices := range dataset.byCategory {
+		dataset.categories = append(dataset.categories, models.Category{
+			Name:  categoryNames[key],
+			Count: len(indices),
+		})
+	}
+	sort.Slice(dataset.categories, func(i, j int) bool {
+		return normalizeCategory(dataset.categories[i].Name) < normalizeCategory(dataset.categories[j].Name)
+	})
+
+	dataset.search = newSearchIndex(texts)
+
+	dataset.version = datasetVersion(records)
+
+	dataset.byIDOrder = make([]int, len(dataset.jokes))
+	for i := range dataset.byIDOrder {
+		dataset.byIDOrder[i] = i
+	}
+	sort.Slice(dataset.byIDOrder, func(i, j int) bool {
+		return compareIDs(dataset.jokes[dataset.byIDOrder[i]].ID, dataset.jokes[dataset.byIDOrder[j]].ID) < 0
+	})
+
+	return dataset, nil
+}
+
+// MemoryJokeRepository keeps the whole dataset in memory, indexed by ID
+type MemoryJokeRepository struct {
+	dataset atomic.Pointer[jokeDataset]
+}
+
+// NewMemoryJokeRepository creates an empty in-memory repository
+func NewMemoryJokeRepository() *MemoryJokeRepository {
+	return &MemoryJokeRepository{}
+}
+
+// Load parses raw CSV records and replaces the current dataset
+func (r *MemoryJokeRepository) Load(records [][]string) error {
+	dataset, err := newJokeDataset(records)
+	if err != nil {
+		return err
+	}
+
+	r.replace(dataset)
+	return nil
+}
+
+// replace atomically swaps in a new dataset; readers keep their old snapshot
+func (r *MemoryJokeRepository) replace(dataset *jokeDataset) {
+	r.dataset.Store(dataset)
+}
+
+// GetRandom returns a joke picked among all jokes or those of the given categories
+func (r *MemoryJokeRepository) GetRandom(seed string, categories []string) (models.Joke, string, error) {
+	jokes, version, err := r.GetRandomSample(seed, categories, 1)
+	if err != nil {
+		return models.Joke{}, "", err
+	}
+	return jokes[0], version, nil
+}
+
+// GetRandomSample returns up to count distinct jokes picked with seed, and the dataset version
+func (r *MemoryJokeRepository) GetRandomSample(seed string, categories []string, count int) ([]models.Joke, string, error) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return nil, "", ErrNoJokesAvailable
+	}
+
+	pool, err := dataset.pool(categories)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if pool.size == 0 {
+		return nil, "", ErrNoJokesAvailable
+	}
+
+	source := newSeededSource(seed, dataset.version)
+
+	if count > pool.size {
+		count = pool.size
+	}
+
+	// Partial Fisher-Yates keeping only the displaced entries
+	swapped := make(map[int]int, count)
+	jokes := make([]models.Joke, 0, count)
+	for i := 0; i < count; i++ {
+		j := i + source.Intn(pool.size-i)
+
+		picked, ok := swapped[j]
+		if !ok {
+			picked = j
+		}
+		current, ok := swapped[i]
+		if !ok {
+			current = i
+		}
+		swapped[j] = current
+
+		jokes = append(jokes, dataset.jokes[pool.at(picked)])
+	}
+
+	return jokes, dataset.version, nil
+}
+
+// GetDeterministic maps a key to a joke with a stable hash over the jokes ordered by ID
+func (r *MemoryJokeRepository) GetDeterministic(key string) (models.Joke, error) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return models.Joke{}, ErrNoJokesAvailable
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	index := dataset.byIDOrder[hash.Sum64()%uint64(len(dataset.byIDOrder))]
+
+	return dataset.jokes[index], nil
+}
+
+// DrawFromDeck deals count distinct jokes from deck, shuffling a new one with
+// newSeed when it runs out, and returns the number of cards left
+func (r *MemoryJokeRepository) DrawFromDeck(deck *models.SessionDeck, categories []string, count int, newSeed func() string) ([]models.Joke, int, error) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return nil, 0, ErrNoJokesAvailable
+	}
+
+	pool, err := dataset.pool(categories)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if pool.size == 0 {
+		return nil, 0, ErrNoJokesAvailable
+	}
+
+	if count > pool.size {
+		count = pool.size
+	}
+
+	shuffle := func() {
+		deck.Seed = newSeed()
+		deck.Version = dataset.version
+		deck.Position = 0
+	}
+
+	if deck.Seed == "" || deck.Version != dataset.version || deck.Position >= pool.size {
+		shuffle()
+	}
+	order := newPermutation(pool.size, deck.Seed)
+
+	// A response never repeats a joke across two decks
+	dealt := make(map[int]bool, count)
+	jokes := make([]models.Joke, 0, count)
+	for len(jokes) < count {
+		if deck.Position >= pool.size {
+			shuffle()
+			order = newPermutation(pool.size, deck.Seed)
+		}
+
+		index := pool.at(order.at(deck.Position))
+		deck.Position++
+
+		if dealt[index] {
+			continue
+		}
+		dealt[index] = true
+		jokes = append(jokes, dataset.jokes[index])
+	}
+
+	return jokes, pool.size - deck.Position, nil
+}
+
+// GetByIDs returns the jokes found for the given IDs, in request order, and the IDs that were not found
+func (r *MemoryJokeRepository) GetByIDs(jokeIDs []string) ([]models.Joke, []string) {
+	jokes := []models.Joke{}
+	missing := []string{}
+
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return jokes, append(missing, jokeIDs...)
+	}
+
+	for _, jokeID := range jokeIDs {
+		if index, ok := dataset.byID[jokeID]; ok {
+			jokes = append(jokes, dataset.jokes[index])
+		} else {
+			missing = append(missing, jokeID)
+		}
+	}
+
+	return jokes, missing
+}
+
+// GetByID returns the joke with the given ID
+func (r *MemoryJokeRepository) GetByID(jokeID string) (models.Joke, error) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return models.Joke{}, ErrJokeNotFound
+	}
+
+	index, ok := dataset.byID[jokeID]
+	if !ok {
+		return models.Joke{}, ErrJokeNotFound
+	}
+
+	return dataset.jokes[index], nil
+}
+
+// GetCategories returns the known categories with their joke counts, sorted by name
+func (r *MemoryJokeRepository) GetCategories() []models.Category {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return []models.Category{}
+	}
+
+	categories := make([]models.Category, len(dataset.categories))
+	copy(categories, dataset.categories)
+	return categories
+}
+
+// Search returns one page of jokes matching the query and the total number of matches
+func (r *MemoryJokeRepository) Search(query string, matchAll bool, offset, limit int) ([]models.SearchResult, int) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return []models.SearchResult{}, 0
+	}
+
+	hits := dataset.search.search(query, matchAll)
+	if offset >= len(hits) {
+		return []models.SearchResult{}, len(hits)
+	}
+
+	end := offset + limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	results := make([]models.SearchResult, 0, end-offset)
+	for _, hit := range hits[offset:end] {
+		results = append(results, models.SearchResult{
+			Score: math.Round(hit.Score*1000) / 1000,
+			Joke:  dataset.jokes[hit.Doc],
+		})
+	}
+
+	return results, len(hits)
+}
+
+// List returns up to limit jokes ordered by ID after afterID, and the ID to continue after
+func (r *MemoryJokeRepository) List(afterID string, limit int) ([]models.Joke, string) {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return []models.Joke{}, ""
+	}
+
+	// Search by value so cursors stay valid even if afterID was removed by a reload
+	start := 0
+	if afterID != "" {
+		start = sort.Search(len(dataset.byIDOrder), func(i int) bool {
+			return compareIDs(dataset.jokes[dataset.byIDOrder[i]].ID, afterID) > 0
+		})
+	}
+
+	end := start + limit
+	if end > len(dataset.byIDOrder) {
+		end = len(dataset.byIDOrder)
+	}
+
+	jokes := make([]models.Joke, 0, end-start)
+	for _, index := range dataset.byIDOrder[start:end] {
+		jokes = append(jokes, dataset.jokes[index])
+	}
+
+	if end == len(dataset.byIDOrder) || end == start {
+		return jokes, ""
+	}
+
+	return jokes, dataset.jokes[dataset.byIDOrder[end-1]].ID
+}
+
+// Version returns the fingerprint of the loaded dataset, empty when nothing is loaded
+func (r *MemoryJokeRepository) Version() string {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return ""
+	}
+	return dataset.version
+}
+
+// Count returns the number of loaded jokes
+func (r *MemoryJokeRepository) Count() int {
+	dataset := r.dataset.Load()
+	if dataset == nil {
+		return 0
+	}
+	return len(dataset.jokes)
+}
+
+var jokeRepository = NewMemoryJokeRepository()
+
+// GetJokeRepository returns the application-wide joke repository
+func GetJokeRepository() *MemoryJokeRepository {
+	return jokeRepository
+}
@@ -0,0 +1,305 @@
+package helpers
+
+import (
+	"jokes-provider/models"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// newTestRepository loads a repository with the given jokes, header row first
+func newTestRepository(t *testing.T, records [][]string) *MemoryJokeRepository {
+	t.Helper()
+
+	repo := NewMemoryJokeRepository()
+	if err := repo.Load(records); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return repo
+}
+
+// numberedRecords returns n jokes with IDs 1..n, alternating two categories
+func numberedRecords(n int) [][]string {
+	records := [][]string{{"id", "joke", "category"}}
+	for i := 1; i <= n; i++ {
+		category := "pun"
+		if i%2 == 0 {
+			category = "dad"
+		}
+		records = append(records, []string{strconv.Itoa(i), "joke " + strconv.Itoa(i), category})
+	}
+	return records
+}
+
+func jokeIDs(jokes []models.Joke) []string {
+	ids := make([]string, len(jokes))
+	for i, joke := range jokes {
+		ids[i] = joke.ID
+	}
+	return ids
+}
+
+func TestDrawFromDeckDealsEveryJokeOncePerCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		jokes      int
+		categories []string
+		poolSize   int
+		count      int
+	}{
+		{"one at a time", 10, nil, 10, 1},
+		{"pool size multiple", 12, nil, 12, 3},
+		{"uneven draws", 10, nil, 10, 3},
+		{"whole deck", 7, nil, 7, 7},
+		{"count above pool", 5, nil, 5, 8},
+		{"category", 10, []string{"pun"}, 5, 2},
+		{"two categories", 9, []string{"PUN", " dad "}, 9, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, numberedRecords(tt.jokes))
+
+			seeds := 0
+			newSeed := func() string {
+				seeds++
+				return "seed-" + strconv.Itoa(seeds)
+			}
+
+			var deck models.SessionDeck
+			var dealt []string
+			for len(dealt) < 3*tt.poolSize {
+				jokes, _, err := repo.DrawFromDeck(&deck, tt.categories, tt.count, newSeed)
+				if err != nil {
+					t.Fatalf("DrawFromDeck: %v", err)
+				}
+
+				ids := jokeIDs(jokes)
+				if want := min(tt.count, tt.poolSize); len(ids) != want {
+					t.Fatalf("drew %d jokes, want %d", len(ids), want)
+				}
+				sorted := slices.Clone(ids)
+				slices.Sort(sorted)
+				if len(slices.Compact(sorted)) != len(ids) {
+					t.Fatalf("one draw repeated a joke: %v", ids)
+				}
+				dealt = append(dealt, ids...)
+			}
+
+			// Each deck deals every joke of the pool before any repeats. Draws
+			// that straddle two decks skip cards, so only the first deck is
+			// aligned unless draws divide the pool evenly.
+			decks := 1
+			if tt.poolSize%min(tt.count, tt.poolSize) == 0 {
+				decks = 3
+			}
+			for deck := range decks {
+				cycle := slices.Clone(dealt[deck*tt.poolSize : (deck+1)*tt.poolSize])
+				slices.Sort(cycle)
+				if len(slices.Compact(cycle)) != tt.poolSize {
+					t.Errorf("deck %d repeated a joke: %v", deck, dealt[deck*tt.poolSize:(deck+1)*tt.poolSize])
+				}
+			}
+		})
+	}
+}
+
+func TestDrawFromDeckReportsRemainingCards(t *testing.T) {
+	repo := newTestRepository(t, numberedRecords(6))
+
+	var deck models.SessionDeck
+	newSeed := func() string { return "fixed" }
+	for _, want := range []int{4, 2, 0, 4} {
+		_, remaining, err := repo.DrawFromDeck(&deck, nil, 2, newSeed)
+		if err != nil {
+			t.Fatalf("DrawFromDeck: %v", err)
+		}
+		if remaining != want {
+			t.Errorf("remaining = %d, want %d", remaining, want)
+		}
+	}
+}
+
+func TestDrawFromDeckReshufflesOnNewVersion(t *testing.T) {
+	repo := newTestRepository(t, numberedRecords(6))
+
+	deck := models.SessionDeck{Seed: "old", Version: "stale", Position: 3}
+	if _, _, err := repo.DrawFromDeck(&deck, nil, 1, func() string { return "new" }); err != nil {
+		t.Fatalf("DrawFromDeck: %v", err)
+	}
+	if deck.Seed != "new" || deck.Version != repo.Version() || deck.Position != 1 {
+		t.Errorf("deck = %+v, want a new deck for version %s", deck, repo.Version())
+	}
+}
+
+func TestListPaginatesWithCursor(t *testing.T) {
+	records := [][]string{
+		{"id", "joke"},
+		{"10", "ten"}, {"b", "bee"}, {"2", "two"}, {"a", "ay"}, {"1", "one"}, {"33", "thirty-three"},
+	}
+	want := []string{"1", "2", "10", "33", "a", "b"}
+
+	for _, limit := range []int{1, 2, 4, 6, 10} {
+		t.Run("limit "+strconv.Itoa(limit), func(t *testing.T) {
+			repo := newTestRepository(t, records)
+
+			var got []string
+			cursor := ""
+			for page := 0; ; page++ {
+				if page > len(want) {
+					t.Fatal("pagination does not end")
+				}
+				jokes, next := repo.List(cursor, limit)
+				if len(jokes) > limit {
+					t.Fatalf("page has %d jokes, limit %d", len(jokes), limit)
+				}
+				got = append(got, jokeIDs(jokes)...)
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+
+			if !slices.Equal(got, want) {
+				t.Errorf("listed %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListCursorOfRemovedJoke(t *testing.T) {
+	tests := []struct {
+		cursor string
+		want   []string
+		next   string
+	}{
+		{"", []string{"1", "3"}, "3"},
+		{"2", []string{"3", "5"}, ""},
+		{"1", []string{"3", "5"}, ""},
+		{"0", []string{"1", "3"}, "3"},
+		{"4", []string{"5"}, ""},
+		{"9", []string{}, ""},
+	}
+
+	repo := newTestRepository(t, [][]string{{"id", "joke"}, {"1", "a"}, {"3", "b"}, {"5", "c"}})
+	for _, tt := range tests {
+		jokes, next := repo.List(tt.cursor, 2)
+		if got := jokeIDs(jokes); !slices.Equal(got, tt.want) || next != tt.next {
+			t.Errorf("List(%q) = %v, %q; want %v, %q", tt.cursor, got, next, tt.want, tt.next)
+		}
+	}
+}
+
+func TestGetDeterministicIsStable(t *testing.T) {
+	records := numberedRecords(50)
+	repo := newTestRepository(t, records)
+
+	// The same jokes in another row order
+	reversed := [][]string{records[0]}
+	for i := len(records) - 1; i > 0; i-- {
+		reversed = append(reversed, records[i])
+	}
+	reordered := newTestRepository(t, reversed)
+
+	picked := make(map[string]bool)
+	for _, key := range []string{"2024-01-01", "2024-01-02", "seed", "", "another seed"} {
+		first, err := repo.GetDeterministic(key)
+		if err != nil {
+			t.Fatalf("GetDeterministic(%q): %v", key, err)
+		}
+		again, _ := repo.GetDeterministic(key)
+		other, _ := reordered.GetDeterministic(key)
+
+		if again.ID != first.ID {
+			t.Errorf("GetDeterministic(%q) = %s then %s", key, first.ID, again.ID)
+		}
+		if other.ID != first.ID {
+			t.Errorf("GetDeterministic(%q) = %s, %s after reordering rows", key, first.ID, other.ID)
+		}
+		picked[first.ID] = true
+	}
+
+	if len(picked) < 2 {
+		t.Error("every key picked the same joke")
+	}
+}
+
+func TestGetDeterministicWithoutDataset(t *testing.T) {
+	if _, err := NewMemoryJokeRepository().GetDeterministic("key"); err != ErrNoJokesAvailable {
+		t.Errorf("error = %v, want ErrNoJokesAvailable", err)
+	}
+}
+
+func TestGetRandomSampleIsReproducible(t *testing.T) {
+	records := numberedRecords(50)
+	repo := newTestRepository(t, records)
+	other := newTestRepository(t, records)
+
+	tests := []struct {
+		name       string
+		categories []string
+		count      int
+	}{
+		{"single", nil, 1},
+		{"sample", nil, 10},
+		{"category", []string{"pun"}, 5},
+		{"whole pool", nil, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, version, err := repo.GetRandomSample("seed-1", tt.categories, tt.count)
+			if err != nil {
+				t.Fatalf("GetRandomSample: %v", err)
+			}
+			again, againVersion, _ := repo.GetRandomSample("seed-1", tt.categories, tt.count)
+			// Another replica loading the same data picks the same jokes
+			replica, replicaVersion, _ := other.GetRandomSample("seed-1", tt.categories, tt.count)
+
+			if againVersion != version || replicaVersion != version {
+				t.Fatalf("versions = %s, %s, want %s", againVersion, replicaVersion, version)
+			}
+			if !slices.Equal(jokeIDs(again), jokeIDs(first)) || !slices.Equal(jokeIDs(replica), jokeIDs(first)) {
+				t.Errorf("same seed picked %v, %v and %v", jokeIDs(first), jokeIDs(again), jokeIDs(replica))
+			}
+			if len(first) != tt.count {
+				t.Errorf("picked %d jokes, want %d", len(first), tt.count)
+			}
+		})
+	}
+
+	single, _, _ := repo.GetRandom("seed-1", nil)
+	sample, _, _ := repo.GetRandomSample("seed-1", nil, 1)
+	if single.ID != sample[0].ID {
+		t.Errorf("GetRandom = %s, want the first joke of the sample %s", single.ID, sample[0].ID)
+	}
+
+	seeded, _, _ := repo.GetRandomSample("seed-1", nil, 10)
+	reseeded, _, _ := repo.GetRandomSample("seed-2", nil, 10)
+	if slices.Equal(jokeIDs(seeded), jokeIDs(reseeded)) {
+		t.Errorf("seeds seed-1 and seed-2 both picked %v", jokeIDs(seeded))
+	}
+}
+
+func TestGetRandomSampleReshufflesOnNewVersion(t *testing.T) {
+	records := numberedRecords(50)
+	repo := newTestRepository(t, records)
+	before, version, err := repo.GetRandomSample("seed-1", nil, 10)
+	if err != nil {
+		t.Fatalf("GetRandomSample: %v", err)
+	}
+
+	// Editing one joke changes the version, and with it every pick
+	records[50][1] = "edited joke"
+	if err := repo.Load(records); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	after, newVersion, _ := repo.GetRandomSample("seed-1", nil, 10)
+
+	if newVersion == version {
+		t.Fatalf("version %s did not change after an edit", version)
+	}
+	if slices.Equal(jokeIDs(before), jokeIDs(after)) {
+		t.Errorf("seed picked %v for both versions", jokeIDs(before))
+	}
+}
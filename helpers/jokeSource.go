@@ -0,0 +1,313 @@
+package helpers
+
+import (
+	"errors"
+	"jokes-provider/config"
+	"jokes-provider/utils"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrUnknownJokesSource is returned when JOKES_SOURCE names an unsupported backend
+var ErrUnknownJokesSource = errors.New(utils.ErrMsgUnknownSource)
+
+// ErrInvalidSQLiteTable is returned when the SQLite table name is not a plain identifier
+var ErrInvalidSQLiteTable = errors.New(utils.ErrMsgInvalidTable)
+
+var sqliteTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JokeSource loads the jokes dataset as header-first records, like the CSV file
+type JokeSource interface {
+	Name() string
+	Path() string
+	Load(c *fiber.Ctx) ([][]string, error)
+	// fingerprint identifies the current version of the data, for the watcher
+	fingerprint() (fileFingerprint, bool)
+}
+
+// writableJokeSource is a source the admin API can persist changes to
+type writableJokeSource interface {
+	JokeSource
+	// save replaces the stored data with records, header row first
+	save(c *fiber.Ctx, records [][]string) error
+}
+
+// NewJokeSource returns the source for a JOKES_SOURCE value
+func NewJokeSource(kind, path, sqliteTable string) (JokeSource, error) {
+	switch strings.ToLower(kind) {
+	case utils.JokesSourceCSV:
+		return &csvJokeSource{path: path}, nil
+	case utils.JokesSourceJSONL:
+		return &jsonlJokeSource{path: path}, nil
+	case utils.JokesSourceSQLite:
+		if !sqliteTablePattern.MatchString(sqliteTable) {
+			return nil, ErrInvalidSQLiteTable
+		}
+		return &sqliteJokeSource{path: path, table: sqliteTable}, nil
+	case utils.JokesSourceDir:
+		return &dirJokeSource{path: path}, nil
+	}
+	return nil, ErrUnknownJokesSource
+}
+
+// csvJokeSource reads a single CSV file, keeping its header names as they are
+type csvJokeSource struct {
+	path string
+}
+
+func (s *csvJokeSource) Name() string { return utils.JokesSourceCSV }
+func (s *csvJokeSource) Path() string { return s.path }
+
+func (s *csvJokeSource) Load(c *fiber.Ctx) ([][]string, error) {
+	return config.ReadCSV(c, s.path)
+}
+
+func (s *csvJokeSource) fingerprint() (fileFingerprint, bool) {
+	return statFingerprint(s.path)
+}
+
+func (s *csvJokeSource) save(c *fiber.Ctx, records [][]string) error {
+	return config.WriteCSVAtomic(c, s.path, records)
+}
+
+// jsonlJokeSource reads a JSON Lines file with one joke object per line
+type jsonlJokeSource struct {
+	path string
+}
+
+func (s *jsonlJokeSource) Name() string { return utils.JokesSourceJSONL }
+func (s *jsonlJokeSource) Path() string { return s.path }
+
+func (s *jsonlJokeSource) Load(c *fiber.Ctx) ([][]string, error) {
+	rows, err := config.ReadJSONLines(c, s.path)
+	if err != nil {
+		return nil, err
+	}
+	return recordsFromRows(rows), nil
+}
+
+func (s *jsonlJokeSource) fingerprint() (fileFingerprint, bool) {
+	return statFingerprint(s.path)
+}
+
+// save writes one object per joke, leaving out empty fields
+func (s *jsonlJokeSource) save(c *fiber.Ctx, records [][]string) error {
+	rows := rowsFromRecords(records)
+	for _, row := range rows {
+		for key, value := range row {
+			if value == "" {
+				delete(row, key)
+			}
+		}
+	}
+	return config.WriteJSONLinesAtomic(c, s.path, rows)
+}
+
+// sqliteJokeSource reads every row of one table in a SQLite database file
+type sqliteJokeSource struct {
+	path  string
+	table string
+}
+
+func (s *sqliteJokeSource) Name() string { return utils.JokesSourceSQLite }
+func (s *sqliteJokeSource) Path() string { return s.path }
+
+func (s *sqliteJokeSource) Load(c *fiber.Ctx) ([][]string, error) {
+	records, err := config.ReadSQLiteTable(c, s.path, s.table)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		for i, column := range records[0] {
+			records[0][i] = canonicalColumn(column)
+		}
+	}
+	return records, nil
+}
+
+// fingerprint includes the write-ahead log, which changes first in WAL mode
+func (s *sqliteJokeSource) fingerprint() (fileFingerprint, bool) {
+	current, ok := statFingerprint(s.path)
+	if !ok {
+		return fileFingerprint{}, false
+	}
+	if wal, ok := statFingerprint(s.path + "-wal"); ok {
+		current.size += wal.size
+		if wal.modTime.After(current.modTime) {
+			current.modTime = wal.modTime
+		}
+	}
+	return current, true
+}
+
+// dirJokeSource reads every .csv and .jsonl file in a directory, one category per file
+type dirJokeSource struct {
+	path string
+}
+
+func (s *dirJokeSource) Name() string { return utils.JokesSourceDir }
+func (s *dirJokeSource) Path() string { return s.path }
+
+func (s *dirJokeSource) Load(c *fiber.Ctx) ([][]string, error) {
+	files, err := s.files()
+	if err != nil {
+		config.LogError(c, "Failed to read jokes directory", "file_path", s.path, "error", err.Error())
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, name := range files {
+		filePath := filepath.Join(s.path, name)
+
+		var fileRows []map[string]string
+		if strings.EqualFold(filepath.Ext(name), utils.FileExtCSV) {
+			records, err := config.ReadCSV(c, filePath)
+			if err != nil {
+				return nil, err
+			}
+			fileRows = rowsFromRecords(records)
+		} else if fileRows, err = config.ReadJSONLines(c, filePath); err != nil {
+			return nil, err
+		}
+
+		category := strings.TrimSuffix(name, filepath.Ext(name))
+		categoryColumn := configuredColumns().category
+		for _, row := range fileRows {
+			row = canonicalRow(row)
+			if strings.TrimSpace(row[categoryColumn]) == "" {
+				row[categoryColumn] = category
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return recordsFromRows(rows), nil
+}
+
+// files lists the data files in the directory, sorted by name
+func (s *dirJokeSource) files() ([]string, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext == utils.FileExtCSV || ext == utils.FileExtJSONL {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// fingerprint combines the directory and its data files
+func (s *dirJokeSource) fingerprint() (fileFingerprint, bool) {
+	current, ok := statFingerprint(s.path)
+	if !ok {
+		return fileFingerprint{}, false
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return fileFingerprint{}, false
+	}
+
+	current.size = 0
+	current.files = len(files)
+	for _, name := range files {
+		file, ok := statFingerprint(filepath.Join(s.path, name))
+		if !ok {
+			continue
+		}
+		current.size += file.size
+		if file.modTime.After(current.modTime) {
+			current.modTime = file.modTime
+		}
+	}
+	return current, true
+}
+
+// canonicalColumn maps a key to the configured joke column it matches case-insensitively
+func canonicalColumn(name string) string {
+	for _, column := range configuredColumns().names() {
+		if strings.EqualFold(name, column) {
+			return column
+		}
+	}
+	return name
+}
+
+func canonicalRow(row map[string]string) map[string]string {
+	canonical := make(map[string]string, len(row))
+	for key, value := range row {
+		canonical[canonicalColumn(key)] = value
+	}
+	return canonical
+}
+
+// rowsFromRecords turns header-first records into one map per row
+func rowsFromRecords(records [][]string) []map[string]string {
+	if len(records) == 0 {
+		return nil
+	}
+
+	headers := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(headers))
+		for i, header := range headers {
+			if i < len(record) {
+				row[header] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// recordsFromRows turns rows into header-first records, the joke columns first
+func recordsFromRows(rows []map[string]string) [][]string {
+	present := make(map[string]bool)
+	for i, row := range rows {
+		rows[i] = canonicalRow(row)
+		for key := range rows[i] {
+			present[key] = true
+		}
+	}
+
+	var headers, extra []string
+	for _, column := range configuredColumns().names() {
+		if present[column] {
+			headers = append(headers, column)
+			delete(present, column)
+		}
+	}
+	for key := range present {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+	headers = append(headers, extra...)
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, headers)
+	for _, row := range rows {
+		record := make([]string, len(headers))
+		for i, header := range headers {
+			record[i] = row[header]
+		}
+		records = append(records, record)
+	}
+	return records
+}
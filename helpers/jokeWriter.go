@@ -0,0 +1,246 @@
+package helpers
+
+import (
+	"errors"
+	"jokes-provider/config"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrSourceReadOnly is returned when the configured jokes source cannot be written to
+var ErrSourceReadOnly = errors.New(utils.ErrMsgSourceReadOnly)
+
+// InvalidJokeError is returned when a joke submitted for writing is rejected
+type InvalidJokeError struct {
+	Reason string
+}
+
+func (e *InvalidJokeError) Error() string {
+	return "invalid joke: " + e.Reason
+}
+
+// SkippedRowsError is returned when a write would drop source rows the dataset left out
+type SkippedRowsError struct {
+	Count int
+}
+
+func (e *SkippedRowsError) Error() string {
+	return "jokes source has " + strconv.Itoa(e.Count) + " duplicate or incomplete rows"
+}
+
+// JokeConflictError is returned when created jokes reuse existing IDs
+type JokeConflictError struct {
+	IDs []string
+}
+
+func (e *JokeConflictError) Error() string {
+	return "joke already exists: " + strings.Join(e.IDs, ",")
+}
+
+// jokesUpdateMu serializes admin writes and watcher reloads
+var jokesUpdateMu sync.Mutex
+
+// activeJokeSource is the source the repository was loaded from
+var activeJokeSource JokeSource
+
+// GetJokeSource returns the source the jokes were loaded from
+func GetJokeSource() JokeSource {
+	return activeJokeSource
+}
+
+// IsJokeSourceWritable reports whether the admin API can persist changes
+func IsJokeSourceWritable() bool {
+	_, ok := activeJokeSource.(writableJokeSource)
+	return ok
+}
+
+// CreateJokes adds new jokes, numbering those without an ID, and persists the dataset
+func CreateJokes(c *fiber.Ctx, jokes []models.Joke) ([]models.Joke, error) {
+	err := writeJokes(c, jokes, func(rows []models.Joke) ([]models.Joke, error) {
+		existing := make(map[string]bool, len(rows))
+		nextID := int64(1)
+		for _, row := range rows {
+			existing[row.ID] = true
+			if id, err := strconv.ParseInt(row.ID, 10, 64); err == nil && id >= nextID {
+				nextID = id + 1
+			}
+		}
+
+		// Explicit IDs are reserved first, so generated IDs never collide with them
+		var conflicts []string
+		for _, joke := range jokes {
+			if joke.ID != "" {
+				if existing[joke.ID] {
+					conflicts = append(conflicts, joke.ID)
+				}
+				existing[joke.ID] = true
+			}
+		}
+		if len(conflicts) > 0 {
+			return nil, &JokeConflictError{IDs: conflicts}
+		}
+
+		for i := range jokes {
+			if jokes[i].ID == "" {
+				for existing[strconv.FormatInt(nextID, 10)] {
+					nextID++
+				}
+				jokes[i].ID = strconv.FormatInt(nextID, 10)
+				existing[jokes[i].ID] = true
+			}
+			rows = append(rows, jokes[i])
+		}
+
+		return rows, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jokes, nil
+}
+
+// UpdateJoke replaces the fields of an existing joke and persists the dataset
+func UpdateJoke(c *fiber.Ctx, jokeID string, joke models.Joke) (models.Joke, error) {
+	jokes := []models.Joke{joke}
+
+	err := writeJokes(c, jokes, func(rows []models.Joke) ([]models.Joke, error) {
+		if id := jokes[0].ID; id != "" && id != jokeID {
+			return nil, &InvalidJokeError{Reason: "ID cannot be changed"}
+		}
+		jokes[0].ID = jokeID
+
+		for i, row := range rows {
+			if row.ID == jokeID {
+				rows[i] = jokes[0]
+				return rows, nil
+			}
+		}
+		return nil, ErrJokeNotFound
+	})
+	if err != nil {
+		return models.Joke{}, err
+	}
+
+	return jokes[0], nil
+}
+
+// DeleteJoke removes a joke, other than the last one, and persists the dataset
+func DeleteJoke(c *fiber.Ctx, jokeID string) error {
+	return writeJokes(c, nil, func(rows []models.Joke) ([]models.Joke, error) {
+		for i, row := range rows {
+			if row.ID == jokeID {
+				return append(rows[:i], rows[i+1:]...), nil
+			}
+		}
+		return nil, ErrJokeNotFound
+	})
+}
+
+// writeJokes applies change to a copy of the jokes, persists it and only then swaps it in
+func writeJokes(c *fiber.Ctx, submitted []models.Joke, change func(rows []models.Joke) ([]models.Joke, error)) error {
+	source, ok := activeJokeSource.(writableJokeSource)
+	if !ok {
+		return ErrSourceReadOnly
+	}
+
+	jokesUpdateMu.Lock()
+	defer jokesUpdateMu.Unlock()
+
+	columns := configuredColumns()
+	headers := columns.names()
+	var rows []models.Joke
+	if dataset := GetJokeRepository().dataset.Load(); dataset != nil {
+		if dataset.skipped > 0 {
+			return &SkippedRowsError{Count: dataset.skipped}
+		}
+		headers = append([]string(nil), dataset.headers...)
+		rows = append([]models.Joke(nil), dataset.jokes...)
+	}
+
+	for i, joke := range submitted {
+		var err error
+		if submitted[i], headers, err = normalizeJoke(columns, headers, joke); err != nil {
+			return err
+		}
+	}
+
+	rows, err := change(rows)
+	if err != nil {
+		return err
+	}
+
+	records := recordsFromJokes(columns, headers, rows)
+
+	dataset, err := newJokeDataset(records)
+	if err != nil {
+		return err
+	}
+
+	if err := source.save(c, records); err != nil {
+		return err
+	}
+
+	GetJokeRepository().replace(dataset)
+	recordDatasetLoad()
+	acknowledgeJokesSource()
+
+	config.LogInfo(c, "Jokes dataset updated", "source", source.Name(), "file_path", source.Path(), "joke_count", len(dataset.jokes))
+	return nil
+}
+
+// normalizeJoke validates a submitted joke and maps its attributes onto the
+// dataset columns, returning the possibly extended headers
+func normalizeJoke(columns jokeColumns, headers []string, joke models.Joke) (models.Joke, []string, error) {
+	normalized := models.Joke{
+		ID:       strings.TrimSpace(joke.ID),
+		Joke:     strings.TrimSpace(joke.Joke),
+		Category: strings.TrimSpace(joke.Category),
+	}
+
+	if normalized.Joke == "" {
+		return models.Joke{}, nil, &InvalidJokeError{Reason: "joke text is required"}
+	}
+	if strings.ContainsAny(normalized.ID, "/?#") {
+		return models.Joke{}, nil, &InvalidJokeError{Reason: "ID cannot contain '/', '?' or '#'"}
+	}
+
+	// The source may lack the optional joke columns
+	if columnIndex(headers, columns.joke) == -1 {
+		headers = append(headers, columns.joke)
+	}
+	if normalized.Category != "" && columnIndex(headers, columns.category) == -1 {
+		headers = append(headers, columns.category)
+	}
+
+	for key, value := range joke.Attributes {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if columns.isMapped(key) {
+			return models.Joke{}, nil, &InvalidJokeError{Reason: "attribute " + key + " is a joke field"}
+		}
+
+		column := key
+		if i := columnIndex(headers, key); i != -1 {
+			column = headers[i]
+		} else {
+			headers = append(headers, column)
+		}
+
+		if value = strings.TrimSpace(value); value != "" {
+			if normalized.Attributes == nil {
+				normalized.Attributes = make(map[string]string)
+			}
+			normalized.Attributes[column] = value
+		}
+	}
+
+	return normalized, headers, nil
+}
@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"errors"
+	"jokes-provider/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadTestSource writes data to a CSV file and loads it as the active source
+func loadTestSource(t *testing.T, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "jokes.csv")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewJokeSource("csv", path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadJokes(nil, source); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWriteJokesKeepsSkippedRows(t *testing.T) {
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"create", func() error {
+			_, err := CreateJokes(nil, []models.Joke{{Joke: "new"}})
+			return err
+		}},
+		{"update", func() error {
+			_, err := UpdateJoke(nil, "2", models.Joke{Joke: "changed"})
+			return err
+		}},
+		{"delete", func() error {
+			return DeleteJoke(nil, "2")
+		}},
+	}
+
+	sources := []struct {
+		name    string
+		data    string
+		skipped int
+	}{
+		{"duplicate ID", "id,joke\n1,first\n1,second\n2,third\n", 1},
+		{"missing ID field", "joke,id\nfirst,1\nno id\nthird,2\n", 1},
+	}
+
+	for _, source := range sources {
+		for _, write := range writes {
+			t.Run(source.name+"/"+write.name, func(t *testing.T) {
+				path := loadTestSource(t, source.data)
+
+				var skippedErr *SkippedRowsError
+				if err := write.write(); !errors.As(err, &skippedErr) {
+					t.Fatalf("write error = %v, want SkippedRowsError", err)
+				}
+				if skippedErr.Count != source.skipped {
+					t.Errorf("skipped rows = %d, want %d", skippedErr.Count, source.skipped)
+				}
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != source.data {
+					t.Errorf("source changed to %q, want %q", data, source.data)
+				}
+			})
+		}
+	}
+}
+
+func TestWriteJokesKeepsAllRows(t *testing.T) {
+	path := loadTestSource(t, "id,joke,author\n1,first,ann\n2,second,\n")
+
+	created, err := CreateJokes(nil, []models.Joke{{Joke: "third"}})
+	if err != nil {
+		t.Fatalf("CreateJokes: %v", err)
+	}
+	if created[0].ID != "3" {
+		t.Errorf("created ID = %q, want 3", created[0].ID)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id,joke,author\n1,first,ann\n2,second,\n3,third,\n"
+	if string(data) != want {
+		t.Errorf("source = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/metrics"
+	"jokes-provider/models"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileFingerprint identifies a version of the jokes data
+type fileFingerprint struct {
+	modTime time.Time
+	size    int64
+	files   int
+}
+
+func statFingerprint(filePath string) (fileFingerprint, bool) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fileFingerprint{}, false
+	}
+	return fileFingerprint{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+// datasetStatus tracks load and reload outcomes for the metadata endpoint
+type datasetStatus struct {
+	mu           sync.RWMutex
+	loadedAt     time.Time
+	lastReloadAt time.Time
+	lastErrorAt  time.Time
+	lastError    string
+	reloadCount  int64
+	failureCount int64
+}
+
+var datasetState = &datasetStatus{}
+
+func recordDatasetLoad() {
+	datasetState.mu.Lock()
+	defer datasetState.mu.Unlock()
+	datasetState.loadedAt = time.Now()
+	metrics.ObserveDatasetLoad(GetJokeRepository().Count(), datasetState.loadedAt)
+}
+
+func recordDatasetReload() {
+	datasetState.mu.Lock()
+	defer datasetState.mu.Unlock()
+	datasetState.loadedAt = time.Now()
+	datasetState.lastReloadAt = datasetState.loadedAt
+	datasetState.reloadCount++
+	metrics.ObserveDatasetLoad(GetJokeRepository().Count(), datasetState.loadedAt)
+	metrics.ObserveDatasetReload(true)
+}
+
+func recordDatasetFailure(err error) {
+	datasetState.mu.Lock()
+	defer datasetState.mu.Unlock()
+	datasetState.lastErrorAt = time.Now()
+	datasetState.lastError = err.Error()
+	datasetState.failureCount++
+}
+
+// GetDatasetInfo returns the current dataset size and reload history
+func GetDatasetInfo() models.DatasetInfo {
+	datasetState.mu.RLock()
+	defer datasetState.mu.RUnlock()
+
+	info := models.DatasetInfo{
+		JokeCount:      GetJokeRepository().Count(),
+		Source:         config.AppConfig.JokesSource,
+		Version:        GetJokeRepository().Version(),
+		ReloadEnabled:  config.AppConfig.JokesReloadEnabled,
+		ReloadInterval: config.AppConfig.JokesReloadInterval,
+		ReloadCount:    datasetState.reloadCount,
+		FailureCount:   datasetState.failureCount,
+		LastError:      datasetState.lastError,
+	}
+
+	if !datasetState.loadedAt.IsZero() {
+		info.LoadedAt = datasetState.loadedAt.Format(time.RFC3339)
+	}
+	if !datasetState.lastReloadAt.IsZero() {
+		info.LastReloadAt = datasetState.lastReloadAt.Format(time.RFC3339)
+	}
+	if !datasetState.lastErrorAt.IsZero() {
+		info.LastErrorAt = datasetState.lastErrorAt.Format(time.RFC3339)
+	}
+
+	return info
+}
+
+// JokesWatcher polls the jokes source and reloads the repository when it changes
+type JokesWatcher struct {
+	source   JokeSource
+	interval time.Duration
+	current  fileFingerprint
+	pending  fileFingerprint
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+var jokesWatcher *JokesWatcher
+
+// StartJokesWatcher starts polling the jokes source in the background
+func StartJokesWatcher(source JokeSource, interval time.Duration) {
+	current, _ := source.fingerprint()
+
+	jokesWatcher = &JokesWatcher{
+		source:   source,
+		interval: interval,
+		current:  current,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go jokesWatcher.run()
+
+	config.LogInfo(nil, "Jokes file watcher started", "source", source.Name(), "file_path", source.Path(), "interval", interval.String())
+}
+
+// StopJokesWatcher stops the background watcher, if running
+func StopJokesWatcher() {
+	if jokesWatcher == nil {
+		return
+	}
+
+	close(jokesWatcher.stop)
+	<-jokesWatcher.done
+	jokesWatcher = nil
+}
+
+func (w *JokesWatcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll reloads once a changed fingerprint has been stable for a full interval
+func (w *JokesWatcher) poll() {
+	jokesUpdateMu.Lock()
+	defer jokesUpdateMu.Unlock()
+
+	observed, ok := w.source.fingerprint()
+	if !ok || observed == w.current {
+		w.pending = fileFingerprint{}
+		return
+	}
+
+	if observed != w.pending {
+		w.pending = observed
+		return
+	}
+
+	w.current = observed
+	w.pending = fileFingerprint{}
+	w.reload()
+}
+
+func (w *JokesWatcher) reload() {
+	config.LogInfo(nil, "Jokes file changed, reloading", "source", w.source.Name(), "file_path", w.source.Path())
+
+	dataset, err := loadJokesDataset(nil, w.source)
+	if err != nil {
+		recordDatasetFailure(err)
+		metrics.ObserveDatasetReload(false)
+		config.LogError(nil, "Jokes reload failed, keeping previous dataset", "file_path", w.source.Path(), "error", err.Error(), "joke_count", GetJokeRepository().Count())
+		return
+	}
+
+	previousCount := GetJokeRepository().Count()
+	GetJokeRepository().replace(dataset)
+	recordDatasetReload()
+
+	config.LogInfo(nil, "Jokes reloaded", "file_path", w.source.Path(), "previous_count", previousCount, "joke_count", len(dataset.jokes))
+}
+
+// acknowledgeJokesSource marks the service's own writes as loaded; callers hold jokesUpdateMu
+func acknowledgeJokesSource() {
+	watcher := jokesWatcher
+	if watcher == nil {
+		return
+	}
+
+	watcher.current, _ = watcher.source.fingerprint()
+	watcher.pending = fileFingerprint{}
+}
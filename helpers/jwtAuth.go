@@ -0,0 +1,298 @@
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jokes-provider/models"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidJWTConfig is returned when the JWT settings cannot be used
+var ErrInvalidJWTConfig = errors.New("invalid JWT configuration")
+
+// ErrUnknownSigningKey is returned when no configured key matches a token
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// JWTSettings configures how bearer tokens are validated
+type JWTSettings struct {
+	Algorithms []string
+	Secret     string
+	JWKSFile   string
+	Issuer     string
+	Audience   string
+	Leeway     time.Duration
+	ScopeClaim string
+}
+
+// jwtVerifier holds the keys and parser options for token validation
+type jwtVerifier struct {
+	secret     []byte
+	keys       map[string]any
+	parser     *jwt.Parser
+	scopeClaim string
+}
+
+var tokenVerifier *jwtVerifier
+
+// jsonWebKey is the subset of RFC 7517 fields needed for RSA, EC and symmetric keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// ConfigureJWT loads the signing keys and prepares token validation
+func ConfigureJWT(settings JWTSettings) error {
+	if len(settings.Algorithms) == 0 {
+		return fmt.Errorf("%w: no algorithms allowed", ErrInvalidJWTConfig)
+	}
+	for _, algorithm := range settings.Algorithms {
+		if jwt.GetSigningMethod(algorithm) == nil {
+			return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidJWTConfig, algorithm)
+		}
+	}
+
+	verifier := &jwtVerifier{
+		keys:       map[string]any{},
+		scopeClaim: settings.ScopeClaim,
+	}
+	if settings.Secret != "" {
+		verifier.secret = []byte(settings.Secret)
+	}
+
+	if settings.JWKSFile != "" {
+		keys, err := loadJWKS(settings.JWKSFile)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidJWTConfig, err)
+		}
+		verifier.keys = keys
+	}
+
+	if verifier.secret == nil && len(verifier.keys) == 0 {
+		return fmt.Errorf("%w: set a secret or a JWKS file", ErrInvalidJWTConfig)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(settings.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(settings.Leeway),
+	}
+	if settings.Issuer != "" {
+		options = append(options, jwt.WithIssuer(settings.Issuer))
+	}
+	if settings.Audience != "" {
+		options = append(options, jwt.WithAudience(settings.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	tokenVerifier = verifier
+	return nil
+}
+
+// ParseToken validates a signed token and returns its subject and scopes
+func ParseToken(tokenString string) (*models.AuthToken, error) {
+	if tokenVerifier == nil {
+		return nil, ErrInvalidJWTConfig
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := tokenVerifier.parser.ParseWithClaims(tokenString, claims, tokenVerifier.keyFor); err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims.GetSubject()
+	issuer, _ := claims.GetIssuer()
+
+	return &models.AuthToken{
+		Subject: subject,
+		Issuer:  issuer,
+		Scopes:  scopesFromClaim(claims[tokenVerifier.scopeClaim]),
+	}, nil
+}
+
+// LooksLikeJWT tells signed tokens apart from opaque bearer API keys
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// keyFor picks the verification key for a token; HMAC tokens only get the shared secret
+func (v *jwtVerifier) keyFor(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if key, ok := v.keys[kid].([]byte); ok && kid != "" {
+			return key, nil
+		}
+		if v.secret != nil {
+			return v.secret, nil
+		}
+		return nil, ErrUnknownSigningKey
+	}
+
+	if kid != "" {
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, ErrUnknownSigningKey
+	}
+
+	// Without a key ID, accept the token only if exactly one key fits the algorithm
+	var match any
+	for _, key := range v.keys {
+		if keyMatchesMethod(key, token.Method) {
+			if match != nil {
+				return nil, ErrUnknownSigningKey
+			}
+			match = key
+		}
+	}
+	if match == nil {
+		return nil, ErrUnknownSigningKey
+	}
+	return match, nil
+}
+
+func keyMatchesMethod(key any, method jwt.SigningMethod) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	}
+	return false
+}
+
+// scopesFromClaim accepts a space-separated string or a list of strings
+func scopesFromClaim(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		scopes := make([]string, 0, len(value))
+		for _, item := range value {
+			if scope, ok := item.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+// loadJWKS reads a local JSON Web Key Set, indexed by key ID
+func loadJWKS(filePath string) (map[string]any, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d (%q): %v", filePath, i, jwk.Kid, err)
+		}
+
+		// Keys without an ID get a positional one, so they can still be matched by type
+		kid := jwk.Kid
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("%s: duplicate key ID %q", filePath, kid)
+		}
+		keys[kid] = key
+	}
+
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeKeyPart(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeKeyPart(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeKeyPart(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeKeyPart(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid EC coordinates")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	case "oct":
+		return decodeKeyPart(jwk.K)
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func decodeKeyPart(value string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
@@ -0,0 +1,265 @@
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret-of-at-least-32-bytes!"
+
+// testJWTKeys are the signing keys published in the test JWKS
+type testJWTKeys struct {
+	rsa  *rsa.PrivateKey
+	ec   *ecdsa.PrivateKey
+	jwks string
+}
+
+func newTestJWTKeys(t *testing.T) testJWTKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating EC key: %v", err)
+	}
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	ecPoint, err := ecKey.PublicKey.Bytes()
+	if err != nil {
+		t.Fatalf("encoding EC key: %v", err)
+	}
+	set := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa", "use": "sig",
+			"n": encode(rsaKey.N.Bytes()),
+			"e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec", "crv": "P-256",
+			"x": encode(ecPoint[1:33]),
+			"y": encode(ecPoint[33:]),
+		},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("encoding JWKS: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("writing JWKS: %v", err)
+	}
+	return testJWTKeys{rsa: rsaKey, ec: ecKey, jwks: path}
+}
+
+// publicKeyPEM is the RSA public key as an attacker would find it published
+func (k testJWTKeys) publicKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&k.rsa.PublicKey)
+	if err != nil {
+		t.Fatalf("encoding public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func configureTestJWT(t *testing.T, settings JWTSettings) {
+	t.Helper()
+	if settings.Algorithms == nil {
+		settings.Algorithms = []string{"HS256", "RS256", "ES256"}
+	}
+	if settings.ScopeClaim == "" {
+		settings.ScopeClaim = "scope"
+	}
+	if err := ConfigureJWT(settings); err != nil {
+		t.Fatalf("ConfigureJWT error: %v", err)
+	}
+	t.Cleanup(func() { tokenVerifier = nil })
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example",
+		"aud":   "jokes",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "jokes:read jokes:write",
+	}
+}
+
+func withClaims(changes jwt.MapClaims) jwt.MapClaims {
+	claims := validClaims()
+	for name, value := range changes {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func TestParseTokenRejectsAlgorithmConfusion(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	publicPEM := keys.publicKeyPEM(t)
+
+	tests := []struct {
+		name   string
+		secret string
+		token  func() string
+	}{
+		{"HMAC with PEM public key", "", func() string {
+			return signToken(t, jwt.SigningMethodHS256, publicPEM, "rsa", validClaims())
+		}},
+		{"HMAC with modulus", "", func() string {
+			return signToken(t, jwt.SigningMethodHS256, keys.rsa.N.Bytes(), "rsa", validClaims())
+		}},
+		{"HMAC without key ID", "", func() string {
+			return signToken(t, jwt.SigningMethodHS256, publicPEM, "", validClaims())
+		}},
+		{"HMAC with public key next to secret", testJWTSecret, func() string {
+			return signToken(t, jwt.SigningMethodHS256, publicPEM, "rsa", validClaims())
+		}},
+		{"unsigned token", "", func() string {
+			return signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "rsa", validClaims())
+		}},
+		{"RSA token with EC key ID", "", func() string {
+			return signToken(t, jwt.SigningMethodRS256, keys.rsa, "ec", validClaims())
+		}},
+		{"unknown key ID", "", func() string {
+			return signToken(t, jwt.SigningMethodRS256, keys.rsa, "other", validClaims())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configureTestJWT(t, JWTSettings{JWKSFile: keys.jwks, Secret: tt.secret})
+			if token, err := ParseToken(tt.token()); err == nil {
+				t.Fatalf("ParseToken accepted token for %q", token.Subject)
+			}
+		})
+	}
+}
+
+func TestParseTokenAcceptsConfiguredKeys(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	configureTestJWT(t, JWTSettings{JWKSFile: keys.jwks, Secret: testJWTSecret})
+
+	tokens := map[string]string{
+		"RSA with key ID":       signToken(t, jwt.SigningMethodRS256, keys.rsa, "rsa", validClaims()),
+		"RSA without key ID":    signToken(t, jwt.SigningMethodRS256, keys.rsa, "", validClaims()),
+		"EC without key ID":     signToken(t, jwt.SigningMethodES256, keys.ec, "", validClaims()),
+		"HMAC with shared key":  signToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), "", validClaims()),
+		"HMAC with RSA key ID":  signToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), "rsa", validClaims()),
+		"scopes as claim array": signToken(t, jwt.SigningMethodES256, keys.ec, "ec", withClaims(jwt.MapClaims{"scope": []string{"jokes:read", "jokes:write"}})),
+	}
+
+	for name, tokenString := range tokens {
+		t.Run(name, func(t *testing.T) {
+			token, err := ParseToken(tokenString)
+			if err != nil {
+				t.Fatalf("ParseToken error: %v", err)
+			}
+			if token.Subject != "user-1" || len(token.Scopes) != 2 || token.Scopes[1] != "jokes:write" {
+				t.Errorf("ParseToken = %+v, want subject user-1 with two scopes", token)
+			}
+		})
+	}
+}
+
+func TestParseTokenValidatesClaims(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   error
+	}{
+		{"valid", validClaims(), nil},
+		{"missing exp", withClaims(jwt.MapClaims{"exp": nil}), jwt.ErrTokenRequiredClaimMissing},
+		{"expired", withClaims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}), jwt.ErrTokenExpired},
+		{"expired within leeway", withClaims(jwt.MapClaims{"exp": now.Add(-10 * time.Second).Unix()}), nil},
+		{"not yet valid", withClaims(jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()}), jwt.ErrTokenNotValidYet},
+		{"not yet valid within leeway", withClaims(jwt.MapClaims{"nbf": now.Add(10 * time.Second).Unix()}), nil},
+		{"missing iss", withClaims(jwt.MapClaims{"iss": nil}), jwt.ErrTokenRequiredClaimMissing},
+		{"wrong iss", withClaims(jwt.MapClaims{"iss": "https://other.example"}), jwt.ErrTokenInvalidIssuer},
+		{"missing aud", withClaims(jwt.MapClaims{"aud": nil}), jwt.ErrTokenRequiredClaimMissing},
+		{"wrong aud", withClaims(jwt.MapClaims{"aud": "other"}), jwt.ErrTokenInvalidAudience},
+		{"aud in list", withClaims(jwt.MapClaims{"aud": []string{"other", "jokes"}}), nil},
+		{"aud list without match", withClaims(jwt.MapClaims{"aud": []string{"other"}}), jwt.ErrTokenInvalidAudience},
+	}
+
+	configureTestJWT(t, JWTSettings{
+		Secret:   testJWTSecret,
+		Issuer:   "https://issuer.example",
+		Audience: "jokes",
+		Leeway:   30 * time.Second,
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseToken(signToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), "", tt.claims))
+			if tt.want == nil && err != nil {
+				t.Fatalf("ParseToken error: %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("ParseToken error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTokenWithoutLeeway(t *testing.T) {
+	configureTestJWT(t, JWTSettings{Secret: testJWTSecret})
+
+	claims := withClaims(jwt.MapClaims{"exp": time.Now().Add(-2 * time.Second).Unix()})
+	_, err := ParseToken(signToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), "", claims))
+	if !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Fatalf("ParseToken error = %v, want %v", err, jwt.ErrTokenExpired)
+	}
+}
+
+func TestConfigureJWTRejectsInvalidSettings(t *testing.T) {
+	tests := map[string]JWTSettings{
+		"no algorithms":     {Algorithms: []string{}, Secret: testJWTSecret},
+		"unknown algorithm": {Algorithms: []string{"HS1024"}, Secret: testJWTSecret},
+		"no keys":           {Algorithms: []string{"HS256"}},
+		"missing JWKS file": {Algorithms: []string{"RS256"}, JWKSFile: filepath.Join(t.TempDir(), "missing.json")},
+	}
+
+	for name, settings := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ConfigureJWT(settings); !errors.Is(err, ErrInvalidJWTConfig) {
+				t.Fatalf("ConfigureJWT error = %v, want %v", err, ErrInvalidJWTConfig)
+			}
+		})
+	}
+}
@@ -1,29 +1,60 @@
 package helpers
 
 import (
+	"errors"
 	"jokes-provider/config"
+	"jokes-provider/tracing"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// LoadJokesFromCSV validates that the CSV file is accessible (no longer caches in memory)
-func LoadJokesFromCSV(c *fiber.Ctx, filePath string) error {
-	if !config.FileExists(filePath) {
-		config.LogError(c, "CSV file not found", "file_path", filePath)
-		return nil
-	}
+// ErrJokesFileNotFound is returned when the jokes file or directory does not exist
+var ErrJokesFileNotFound = errors.New("jokes file not found")
 
-	data, err := config.ReadCSVWithHeaders(c, filePath)
+// LoadJokes reads the jokes source once and loads it into the joke repository
+func LoadJokes(c *fiber.Ctx, source JokeSource) error {
+	activeJokeSource = source
+
+	dataset, err := loadJokesDataset(c, source)
 	if err != nil {
-		config.LogError(c, "Failed to validate CSV file", "file_path", filePath, "error", err.Error())
+		config.LogError(c, "Failed to load jokes", "source", source.Name(), "file_path", source.Path(), "error", err.Error())
+		recordDatasetFailure(err)
 		return nil
 	}
 
-	if len(data) == 0 {
-		config.LogError(c, "CSV file is empty", "file_path", filePath)
-		return nil
-	}
+	GetJokeRepository().replace(dataset)
+	recordDatasetLoad()
 
-	config.LogInfo(c, "CSV file validated", "file_path", filePath, "joke_count", len(data))
+	config.LogInfo(c, "Jokes loaded into memory", "source", source.Name(), "file_path", source.Path(), "joke_count", len(dataset.jokes))
+	if dataset.skipped > 0 {
+		config.LogError(c, "Jokes source has duplicate or incomplete rows, admin writes are disabled", "source", source.Name(), "file_path", source.Path(), "skipped_rows", dataset.skipped)
+	}
 	return nil
 }
+
+// loadJokesDataset reads and validates the source without touching the repository
+func loadJokesDataset(c *fiber.Ctx, source JokeSource) (dataset *jokeDataset, err error) {
+	span := tracing.Start(c, "dataset.load",
+		tracing.AttributeJokesSource.String(source.Name()),
+		tracing.AttributeJokesPath.String(source.Path()),
+	)
+	defer func() {
+		if err != nil {
+			span.Fail(err)
+		} else {
+			span.SetAttributes(tracing.AttributeJokesCount.Int(len(dataset.jokes)))
+		}
+		span.End()
+	}()
+
+	if !config.FileExists(source.Path()) {
+		return nil, ErrJokesFileNotFound
+	}
+
+	records, err := source.Load(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return newJokeDataset(records)
+}
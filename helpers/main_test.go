@@ -0,0 +1,16 @@
+package helpers
+
+import (
+	"jokes-provider/config"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestMain loads the default configuration and logger the helpers rely on
+func TestMain(m *testing.M) {
+	config.LoadEnvVars()
+	config.InitializeLogger(fiber.New())
+	os.Exit(m.Run())
+}
@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+// feistelRounds is enough rounds for a well-mixed shuffle; this is not cryptography
+const feistelRounds = 4
+
+// permutation is a keyed bijection over [0, size), a shuffled deck stored as a seed
+type permutation struct {
+	size     uint64
+	halfBits uint
+	mask     uint64
+	keys     [feistelRounds]uint64
+}
+
+func newPermutation(size int, seed string) permutation {
+	// Balanced Feistel network over the smallest even bit width covering size
+	width := uint(bits.Len64(uint64(size - 1)))
+	if width < 2 {
+		width = 2
+	}
+	if width%2 == 1 {
+		width++
+	}
+
+	p := permutation{
+		size:     uint64(size),
+		halfBits: width / 2,
+		mask:     1<<(width/2) - 1,
+	}
+
+	for round := range p.keys {
+		hash := fnv.New64a()
+		hash.Write([]byte(seed))
+		hash.Write([]byte{byte(round)})
+		p.keys[round] = hash.Sum64()
+	}
+
+	return p
+}
+
+// at returns the deck index at the given position, cycle walking values outside the range
+func (p permutation) at(position int) int {
+	value := uint64(position)
+	for {
+		value = p.encrypt(value)
+		if value < p.size {
+			return int(value)
+		}
+	}
+}
+
+func (p permutation) encrypt(value uint64) uint64 {
+	left, right := value>>p.halfBits, value&p.mask
+	for _, key := range p.keys {
+		left, right = right, left^(mix64(right^key)&p.mask)
+	}
+	return left<<p.halfBits | right
+}
+
+// mix64 is the SplitMix64 finalizer
+func mix64(value uint64) uint64 {
+	value ^= value >> 30
+	value *= 0xbf58476d1ce4e5b9
+	value ^= value >> 27
+	value *= 0x94d049bb133111eb
+	value ^= value >> 31
+	return value
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPermutationIsBijection(t *testing.T) {
+	tests := []struct {
+		size int
+		seed string
+	}{
+		{1, "a"},
+		{2, "a"},
+		{3, "b"},
+		{4, "c"},
+		{7, "d"},
+		{16, "e"},
+		{17, "f"},
+		{100, "g"},
+		{1000, "h"},
+		{4097, "i"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.size), func(t *testing.T) {
+			p := newPermutation(tt.size, tt.seed)
+			seen := make([]bool, tt.size)
+			for position := range tt.size {
+				index := p.at(position)
+				if index < 0 || index >= tt.size {
+					t.Fatalf("at(%d) = %d, out of [0, %d)", position, index, tt.size)
+				}
+				if seen[index] {
+					t.Fatalf("at(%d) = %d, already dealt", position, index)
+				}
+				seen[index] = true
+			}
+		})
+	}
+}
+
+func TestPermutationDependsOnSeed(t *testing.T) {
+	a, b := newPermutation(100, "one"), newPermutation(100, "two")
+	for position := range 100 {
+		if a.at(position) != b.at(position) {
+			return
+		}
+	}
+	t.Error("different seeds gave the same order")
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// seedAlphabet is used for generated seeds so they are safe in URLs
+const seedAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// generatedSeedLength gives about 82 bits of entropy
+const generatedSeedLength = 16
+
+// RandomSource is the randomness used to generate seeds, satisfied by *rand.Rand
+type RandomSource interface {
+	Intn(n int) int
+}
+
+// globalRandomSource uses the goroutine-safe top-level math/rand functions
+type globalRandomSource struct{}
+
+func (globalRandomSource) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// NewGlobalRandomSource returns the default, non-reproducible random source
+func NewGlobalRandomSource() RandomSource {
+	return globalRandomSource{}
+}
+
+// NewRandomSeed generates a URL-safe seed from the given source
+func NewRandomSeed(source RandomSource) string {
+	seed := make([]byte, generatedSeedLength)
+	for i := range seed {
+		seed[i] = seedAlphabet[source.Intn(len(seedAlphabet))]
+	}
+	return string(seed)
+}
+
+// newSeededSource returns a deterministic source for a seed and dataset version
+func newSeededSource(seed, datasetVersion string) *rand.Rand {
+	hash := fnv.New64a()
+	hash.Write([]byte(datasetVersion))
+	hash.Write([]byte{0})
+	hash.Write([]byte(seed))
+	return rand.New(rand.NewSource(int64(hash.Sum64())))
+}
@@ -0,0 +1,475 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"jokes-provider/utils"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrUnknownRateLimitAlgorithm is returned when RATE_LIMIT_ALGORITHM names an unsupported algorithm
+	ErrUnknownRateLimitAlgorithm = errors.New("unknown rate limit algorithm")
+	// ErrInvalidRateLimit is returned when the limit or the window is not positive
+	ErrInvalidRateLimit = errors.New("rate limit and window must be positive")
+)
+
+// RateLimitDecision is the outcome of counting one request
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the full limit is available again
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimiter counts requests per key with one algorithm, atomically in a Lua
+// script when the store shares counters through Redis
+type RateLimiter struct {
+	name      string
+	algorithm rateLimitAlgorithm
+	store     fiber.Storage
+	mu        sync.Mutex
+}
+
+// rateLimitAlgorithm updates the state of one key, in process with take or in
+// Redis with script
+type rateLimitAlgorithm interface {
+	take(state []byte, now time.Time) (RateLimitDecision, []byte, time.Duration)
+	script() *redis.Script
+	args(now time.Time) []any
+	decide(reply []int64, now time.Time) RateLimitDecision
+}
+
+// RateLimitScriptRunner is implemented by stores sharing counters through
+// Redis; shared is false while they fall back to process memory
+type RateLimitScriptRunner interface {
+	RunScript(script *redis.Script, key string, args ...any) (reply []int64, shared bool, err error)
+}
+
+// NewRateLimiter returns a limiter allowing maxRequests per window; burst
+// defaults to maxRequests
+func NewRateLimiter(algorithm string, maxRequests int, window time.Duration, burst int, store fiber.Storage) (*RateLimiter, error) {
+	if maxRequests <= 0 || window <= 0 {
+		return nil, ErrInvalidRateLimit
+	}
+	if burst <= 0 {
+		burst = maxRequests
+	}
+
+	var impl rateLimitAlgorithm
+	switch strings.ToLower(algorithm) {
+	case utils.RateLimitFixedWindow:
+		impl = fixedWindow{max: maxRequests, window: window}
+	case utils.RateLimitSlidingWindow:
+		impl = slidingWindow{max: maxRequests, window: window}
+	case utils.RateLimitSlidingLog:
+		impl = slidingLog{max: maxRequests, window: window}
+	case utils.RateLimitTokenBucket:
+		impl = tokenBucket{burst: burst, rate: float64(maxRequests) / window.Seconds()}
+	default:
+		return nil, ErrUnknownRateLimitAlgorithm
+	}
+
+	return &RateLimiter{name: strings.ToLower(algorithm), algorithm: impl, store: store}, nil
+}
+
+// Take counts a request for key unless it is denied
+func (l *RateLimiter) Take(key string, now time.Time) (RateLimitDecision, error) {
+	if runner, ok := l.store.(RateLimitScriptRunner); ok {
+		reply, shared, err := runner.RunScript(l.algorithm.script(), l.name+":"+key, l.algorithm.args(now)...)
+		if err != nil {
+			return RateLimitDecision{}, err
+		}
+		if shared {
+			return l.algorithm.decide(reply, now), nil
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.store.Get(key)
+	if err != nil {
+		return RateLimitDecision{}, err
+	}
+
+	decision, state, ttl := l.algorithm.take(state, now)
+	if state != nil {
+		if err := l.store.Set(key, state, ttl); err != nil {
+			return RateLimitDecision{}, err
+		}
+	}
+
+	return decision, nil
+}
+
+// fixedWindow counts requests in consecutive windows starting at the first request
+type fixedWindow struct {
+	max    int
+	window time.Duration
+}
+
+type fixedWindowState struct {
+	Start int64 `json:"s"`
+	Count int   `json:"c"`
+}
+
+func (a fixedWindow) take(raw []byte, now time.Time) (RateLimitDecision, []byte, time.Duration) {
+	var state fixedWindowState
+	decodeRateLimitState(raw, &state)
+
+	start := time.Unix(0, state.Start)
+	if state.Start == 0 || !now.Before(start.Add(a.window)) {
+		start, state = now, fixedWindowState{Start: now.UnixNano()}
+	}
+	reset := start.Add(a.window).Sub(now)
+
+	if state.Count >= a.max {
+		return RateLimitDecision{Limit: a.max, Reset: reset, RetryAfter: reset}, nil, 0
+	}
+
+	state.Count++
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.max,
+		Remaining: a.max - state.Count,
+		Reset:     reset,
+	}, encodeRateLimitState(state), reset
+}
+
+// fixedWindowScript replies allowed (1 or 0), count and milliseconds left
+var fixedWindowScript = redis.NewScript(`
+local max, window = tonumber(ARGV[1]), tonumber(ARGV[2])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl <= 0 then
+	redis.call('SET', KEYS[1], 1)
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, 1, window}
+end
+local count = tonumber(redis.call('GET', KEYS[1])) or 0
+if count >= max then
+	return {0, count, ttl}
+end
+return {1, redis.call('INCR', KEYS[1]), ttl}
+`)
+
+func (a fixedWindow) script() *redis.Script { return fixedWindowScript }
+
+func (a fixedWindow) args(time.Time) []any {
+	return []any{a.max, a.window.Milliseconds()}
+}
+
+func (a fixedWindow) decide(reply []int64, _ time.Time) RateLimitDecision {
+	count, reset := int(reply[1]), time.Duration(reply[2])*time.Millisecond
+	if reply[0] == 0 {
+		return RateLimitDecision{Limit: a.max, Reset: reset, RetryAfter: reset}
+	}
+	return RateLimitDecision{Allowed: true, Limit: a.max, Remaining: a.max - count, Reset: reset}
+}
+
+// slidingWindow weights the previous window's count by its overlap with the rolling window
+type slidingWindow struct {
+	max    int
+	window time.Duration
+}
+
+type slidingWindowState struct {
+	Start    int64 `json:"s"`
+	Previous int   `json:"p"`
+	Current  int   `json:"c"`
+}
+
+func (a slidingWindow) take(raw []byte, now time.Time) (RateLimitDecision, []byte, time.Duration) {
+	var state slidingWindowState
+	decodeRateLimitState(raw, &state)
+
+	start := now.Truncate(a.window)
+	switch elapsed := start.Sub(time.Unix(0, state.Start)); {
+	case elapsed == a.window:
+		state = slidingWindowState{Previous: state.Current}
+	case elapsed != 0:
+		state = slidingWindowState{}
+	}
+	state.Start = start.UnixNano()
+
+	progress := float64(now.Sub(start)) / float64(a.window)
+	weight := 1 - progress
+	used := float64(state.Previous)*weight + float64(state.Current)
+
+	if used+1 > float64(a.max) {
+		return RateLimitDecision{
+			Limit:      a.max,
+			Reset:      a.reset(state, start, now),
+			RetryAfter: a.retryAfter(state, progress),
+		}, nil, 0
+	}
+
+	state.Current++
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.max,
+		Remaining: max(0, a.max-int(math.Ceil(used+1))),
+		Reset:     a.reset(state, start, now),
+	}, encodeRateLimitState(state), 2 * a.window
+}
+
+// slidingWindowScript replies allowed (1 or 0), window start, previous and current count
+var slidingWindowScript = redis.NewScript(`
+local max, window, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local start = now - now % window
+local state = redis.call('HMGET', KEYS[1], 's', 'p', 'c')
+local s, p, c = tonumber(state[1]) or 0, tonumber(state[2]) or 0, tonumber(state[3]) or 0
+if start - s == window then
+	p, c = c, 0
+elseif start ~= s then
+	p, c = 0, 0
+end
+if p * (1 - (now - start) / window) + c + 1 > max then
+	return {0, start, p, c}
+end
+c = c + 1
+redis.call('HSET', KEYS[1], 's', start, 'p', p, 'c', c)
+redis.call('PEXPIRE', KEYS[1], 2 * window)
+return {1, start, p, c}
+`)
+
+func (a slidingWindow) script() *redis.Script { return slidingWindowScript }
+
+func (a slidingWindow) args(now time.Time) []any {
+	return []any{a.max, a.window.Milliseconds(), now.UnixMilli()}
+}
+
+func (a slidingWindow) decide(reply []int64, now time.Time) RateLimitDecision {
+	start := time.UnixMilli(reply[1])
+	state := slidingWindowState{Previous: int(reply[2]), Current: int(reply[3])}
+	progress := float64(now.Sub(start)) / float64(a.window)
+
+	if reply[0] == 0 {
+		return RateLimitDecision{
+			Limit:      a.max,
+			Reset:      a.reset(state, start, now),
+			RetryAfter: a.retryAfter(state, progress),
+		}
+	}
+
+	used := float64(state.Previous)*(1-progress) + float64(state.Current)
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.max,
+		Remaining: max(0, a.max-int(math.Ceil(used))),
+		Reset:     a.reset(state, start, now),
+	}
+}
+
+// reset is the time until no counted request weighs on the rolling window
+func (a slidingWindow) reset(state slidingWindowState, start, now time.Time) time.Duration {
+	if state.Current == 0 {
+		return start.Add(a.window).Sub(now)
+	}
+	return start.Add(2 * a.window).Sub(now)
+}
+
+// retryAfter solves for the time when the weighted count leaves room for one request
+func (a slidingWindow) retryAfter(state slidingWindowState, progress float64) time.Duration {
+	room := float64(a.max - 1)
+	window := float64(a.window)
+
+	if float64(state.Current) <= room && state.Previous > 0 {
+		target := 1 - (room-float64(state.Current))/float64(state.Previous)
+		return time.Duration((target - progress) * window)
+	}
+
+	untilNext := (1 - progress) * window
+	if state.Current == 0 {
+		return time.Duration(untilNext)
+	}
+	target := max(0, 1-room/float64(state.Current))
+	return time.Duration(untilNext + target*window)
+}
+
+// slidingLog keeps the time of every allowed request in the rolling window
+type slidingLog struct {
+	max    int
+	window time.Duration
+}
+
+func (a slidingLog) take(raw []byte, now time.Time) (RateLimitDecision, []byte, time.Duration) {
+	var log []int64
+	decodeRateLimitState(raw, &log)
+
+	cutoff := now.Add(-a.window).UnixNano()
+	kept := log[:0]
+	for _, at := range log {
+		if at > cutoff {
+			kept = append(kept, at)
+		}
+	}
+	log = kept
+
+	if len(log) >= a.max {
+		retry := time.Unix(0, log[len(log)-a.max]).Add(a.window).Sub(now)
+		reset := time.Unix(0, log[len(log)-1]).Add(a.window).Sub(now)
+		return RateLimitDecision{Limit: a.max, Reset: reset, RetryAfter: retry}, nil, 0
+	}
+
+	log = append(log, now.UnixNano())
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.max,
+		Remaining: a.max - len(log),
+		Reset:     a.window,
+	}, encodeRateLimitState(log), a.window
+}
+
+// slidingLogScript replies allowed (1 or 0), the requests in the window and,
+// when denied, the times of the request that makes room and of the newest one
+var slidingLogScript = redis.NewScript(`
+local max, window, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count >= max then
+	local oldest = redis.call('ZRANGE', KEYS[1], count - max, count - max, 'WITHSCORES')
+	local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+	return {0, count, tonumber(oldest[2]), tonumber(newest[2])}
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return {1, count + 1, 0, 0}
+`)
+
+func (a slidingLog) script() *redis.Script { return slidingLogScript }
+
+// args includes a unique member, so requests in the same millisecond are all kept
+func (a slidingLog) args(now time.Time) []any {
+	member := strconv.FormatInt(now.UnixNano(), 36) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+	return []any{a.max, a.window.Milliseconds(), now.UnixMilli(), member}
+}
+
+func (a slidingLog) decide(reply []int64, now time.Time) RateLimitDecision {
+	if reply[0] == 0 {
+		return RateLimitDecision{
+			Limit:      a.max,
+			Reset:      time.UnixMilli(reply[3]).Add(a.window).Sub(now),
+			RetryAfter: time.UnixMilli(reply[2]).Add(a.window).Sub(now),
+		}
+	}
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.max,
+		Remaining: a.max - int(reply[1]),
+		Reset:     a.window,
+	}
+}
+
+// tokenBucket refills a bucket of burst tokens at rate tokens per second
+type tokenBucket struct {
+	burst int
+	rate  float64
+}
+
+type tokenBucketState struct {
+	Tokens float64 `json:"t"`
+	Last   int64   `json:"l"`
+}
+
+func (a tokenBucket) take(raw []byte, now time.Time) (RateLimitDecision, []byte, time.Duration) {
+	state := tokenBucketState{Tokens: float64(a.burst), Last: now.UnixNano()}
+	decodeRateLimitState(raw, &state)
+
+	elapsed := now.Sub(time.Unix(0, state.Last)).Seconds()
+	if elapsed > 0 {
+		state.Tokens = math.Min(float64(a.burst), state.Tokens+elapsed*a.rate)
+		state.Last = now.UnixNano()
+	}
+
+	if state.Tokens < 1 {
+		return RateLimitDecision{
+			Limit:      a.burst,
+			Reset:      a.refillTime(state.Tokens),
+			RetryAfter: a.seconds(1 - state.Tokens),
+		}, nil, 0
+	}
+
+	state.Tokens--
+	refill := a.refillTime(state.Tokens)
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.burst,
+		Remaining: int(state.Tokens),
+		Reset:     refill,
+	}, encodeRateLimitState(state), refill + time.Second
+}
+
+// tokenBucketScript replies allowed (1 or 0) and the tokens left in millionths
+var tokenBucketScript = redis.NewScript(`
+local burst, rate, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 't', 'l')
+local tokens, last = tonumber(state[1]) or burst, tonumber(state[2]) or now
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) * rate)
+	last = now
+end
+if tokens < 1 then
+	return {0, math.floor(tokens * 1000000)}
+end
+tokens = tokens - 1
+redis.call('HSET', KEYS[1], 't', tostring(tokens), 'l', last)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {1, math.floor(tokens * 1000000)}
+`)
+
+func (a tokenBucket) script() *redis.Script { return tokenBucketScript }
+
+// args passes the rate in tokens per millisecond
+func (a tokenBucket) args(now time.Time) []any {
+	return []any{a.burst, strconv.FormatFloat(a.rate/1000, 'f', -1, 64), now.UnixMilli()}
+}
+
+func (a tokenBucket) decide(reply []int64, _ time.Time) RateLimitDecision {
+	tokens := float64(reply[1]) / 1e6
+	if reply[0] == 0 {
+		return RateLimitDecision{
+			Limit:      a.burst,
+			Reset:      a.refillTime(tokens),
+			RetryAfter: a.seconds(1 - tokens),
+		}
+	}
+	return RateLimitDecision{
+		Allowed:   true,
+		Limit:     a.burst,
+		Remaining: int(tokens),
+		Reset:     a.refillTime(tokens),
+	}
+}
+
+// refillTime is the time until the bucket is full again
+func (a tokenBucket) refillTime(tokens float64) time.Duration {
+	return a.seconds(float64(a.burst) - tokens)
+}
+
+// seconds is the time needed to refill the given number of tokens
+func (a tokenBucket) seconds(tokens float64) time.Duration {
+	return time.Duration(tokens / a.rate * float64(time.Second))
+}
+
+// decodeRateLimitState leaves v unchanged for missing or unreadable state
+func decodeRateLimitState(raw []byte, v any) {
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, v)
+	}
+}
+
+func encodeRateLimitState(v any) []byte {
+	raw, _ := json.Marshal(v)
+	return raw
+}
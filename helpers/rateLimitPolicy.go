@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jokes-provider/config"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRateLimitPolicy is returned when the rate limit policy file cannot be used
+var ErrInvalidRateLimitPolicy = errors.New("invalid rate limit policy")
+
+// DefaultRateLimitExemptRoutes are not rate limited unless the policy file says otherwise
+var DefaultRateLimitExemptRoutes = []string{"/health/*", "/swagger*", "/docs/*"}
+
+// rateLimitPolicy is the loaded policy set with its networks parsed
+type rateLimitPolicy struct {
+	models.RateLimitPolicySet
+	exemptNetworks   []netip.Prefix
+	internalNetworks []netip.Prefix
+}
+
+var activeRateLimitPolicy = &rateLimitPolicy{
+	RateLimitPolicySet: models.RateLimitPolicySet{ExemptRoutes: DefaultRateLimitExemptRoutes},
+}
+
+// LoadRateLimitPolicy loads the policy file, when set, with fallback as the
+// last policy and the source of missing windows and algorithms
+func LoadRateLimitPolicy(filePath string, fallback models.RateLimitPolicy) error {
+	var set models.RateLimitPolicySet
+
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRateLimitPolicy, err)
+		}
+		if err := json.Unmarshal(data, &set); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidRateLimitPolicy, filePath, err)
+		}
+	}
+
+	// An explicit empty list removes the default exemptions
+	if set.ExemptRoutes == nil {
+		set.ExemptRoutes = DefaultRateLimitExemptRoutes
+	}
+
+	names := make(map[string]bool, len(set.Policies))
+	for i := range set.Policies {
+		policy := &set.Policies[i]
+		policy.Name = strings.TrimSpace(policy.Name)
+		if policy.Name == "" || policy.Name == fallback.Name {
+			return fmt.Errorf("%w: policy %d needs a name other than %q", ErrInvalidRateLimitPolicy, i+1, fallback.Name)
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("%w: duplicate policy name %q", ErrInvalidRateLimitPolicy, policy.Name)
+		}
+		names[policy.Name] = true
+
+		for _, tier := range policy.Tiers {
+			if !slices.Contains([]string{utils.RateLimitTierAnonymous, utils.RateLimitTierKeyed, utils.RateLimitTierInternal}, tier) {
+				return fmt.Errorf("%w: policy %q has unknown tier %q", ErrInvalidRateLimitPolicy, policy.Name, tier)
+			}
+		}
+
+		if policy.Exempt {
+			continue
+		}
+		if policy.MaxRequests <= 0 {
+			return fmt.Errorf("%w: policy %q needs a positive max_requests", ErrInvalidRateLimitPolicy, policy.Name)
+		}
+		if policy.Window == "" {
+			policy.Window = fallback.Window
+		}
+		if window, err := time.ParseDuration(policy.Window); err != nil || window <= 0 {
+			return fmt.Errorf("%w: policy %q has an invalid window %q", ErrInvalidRateLimitPolicy, policy.Name, policy.Window)
+		}
+		if policy.Algorithm == "" {
+			policy.Algorithm = fallback.Algorithm
+		}
+	}
+	set.Policies = append(set.Policies, fallback)
+
+	loaded := &rateLimitPolicy{RateLimitPolicySet: set}
+	var err error
+	if loaded.exemptNetworks, err = config.ParseNetworks(set.ExemptNetworks); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRateLimitPolicy, err)
+	}
+	if loaded.internalNetworks, err = config.ParseNetworks(set.InternalNetworks); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRateLimitPolicy, err)
+	}
+
+	activeRateLimitPolicy = loaded
+	return nil
+}
+
+// GetRateLimitPolicy returns the effective policy set
+func GetRateLimitPolicy() models.RateLimitPolicySet {
+	return activeRateLimitPolicy.RateLimitPolicySet
+}
+
+// IsRateLimitExempt reports whether requests to path from ip are never limited
+func IsRateLimitExempt(path, ip string) bool {
+	return MatchRoute(activeRateLimitPolicy.ExemptRoutes, path) || config.InNetworks(activeRateLimitPolicy.exemptNetworks, ip)
+}
+
+// IsInternalNetwork reports whether ip belongs to the internal tier
+func IsInternalNetwork(ip string) bool {
+	return config.InNetworks(activeRateLimitPolicy.internalNetworks, ip)
+}
+
+// MatchRateLimitPolicy returns the first policy covering path for tier
+func MatchRateLimitPolicy(path, tier string) *models.RateLimitPolicy {
+	policies := activeRateLimitPolicy.Policies
+	for i := range policies {
+		policy := &policies[i]
+		if len(policy.Routes) > 0 && !MatchRoute(policy.Routes, path) {
+			continue
+		}
+		if len(policy.Tiers) > 0 && !slices.Contains(policy.Tiers, tier) {
+			continue
+		}
+		return policy
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"errors"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testFallbackPolicy = models.RateLimitPolicy{Name: "default", MaxRequests: 5, Window: "1m", Algorithm: utils.RateLimitFixedWindow}
+
+func loadTestRateLimitPolicy(t *testing.T, content string) error {
+	t.Helper()
+	t.Cleanup(func() {
+		activeRateLimitPolicy = &rateLimitPolicy{RateLimitPolicySet: models.RateLimitPolicySet{ExemptRoutes: DefaultRateLimitExemptRoutes}}
+	})
+
+	var path string
+	if content != "" {
+		path = filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("writing policy file: %v", err)
+		}
+	}
+	return LoadRateLimitPolicy(path, testFallbackPolicy)
+}
+
+func TestRateLimitExemptRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		exempt map[string]bool
+	}{
+		{
+			name: "defaults",
+			exempt: map[string]bool{
+				"/health/liveness":   true,
+				"/health/readiness":  true,
+				"/swagger":           true,
+				"/swagger/doc.json":  true,
+				"/docs/":             true,
+				"/docs/swagger.yaml": true,
+				"/v1/jokes/random":   false,
+				"/v1/metadata":       false,
+				"/healthz":           false,
+			},
+		},
+		{
+			name:   "defaults when omitted",
+			policy: `{"exempt_networks": ["10.0.0.0/8"]}`,
+			exempt: map[string]bool{"/health/liveness": true, "/swagger/index.html": true, "/docs/swagger.json": true, "/v1/jokes": false},
+		},
+		{
+			name:   "explicit list replaces defaults",
+			policy: `{"exempt_routes": ["/v1/jokes/daily"]}`,
+			exempt: map[string]bool{"/v1/jokes/daily": true, "/health/liveness": false, "/swagger/index.html": false, "/docs/swagger.json": false},
+		},
+		{
+			name:   "empty list limits every route",
+			policy: `{"exempt_routes": []}`,
+			exempt: map[string]bool{"/health/liveness": false, "/swagger/index.html": false, "/docs/swagger.json": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := loadTestRateLimitPolicy(t, tt.policy); err != nil {
+				t.Fatalf("LoadRateLimitPolicy error: %v", err)
+			}
+			for path, want := range tt.exempt {
+				if got := IsRateLimitExempt(path, "203.0.113.9"); got != want {
+					t.Errorf("IsRateLimitExempt(%q) = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchRateLimitPolicy(t *testing.T) {
+	err := loadTestRateLimitPolicy(t, `{
+		"exempt_networks": ["10.0.0.0/8"],
+		"internal_networks": ["192.168.0.0/16"],
+		"policies": [
+			{"name": "internal", "tiers": ["internal"], "exempt": true},
+			{"name": "search", "routes": ["/v1/jokes/search"], "tiers": ["anonymous"], "max_requests": 10},
+			{"name": "partners", "tiers": ["keyed"], "max_requests": 1000, "window": "1h"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("LoadRateLimitPolicy error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		tier string
+		want string
+	}{
+		{"/v1/jokes/search", utils.RateLimitTierAnonymous, "search"},
+		{"/v1/jokes/search", utils.RateLimitTierKeyed, "partners"},
+		{"/v1/jokes/search", utils.RateLimitTierInternal, "internal"},
+		{"/v1/jokes/random", utils.RateLimitTierAnonymous, "default"},
+	}
+	for _, tt := range tests {
+		policy := MatchRateLimitPolicy(tt.path, tt.tier)
+		if policy == nil || policy.Name != tt.want {
+			t.Errorf("MatchRateLimitPolicy(%q, %q) = %+v, want %s", tt.path, tt.tier, policy, tt.want)
+		}
+	}
+
+	search := MatchRateLimitPolicy("/v1/jokes/search", utils.RateLimitTierAnonymous)
+	if search.Window != testFallbackPolicy.Window || search.Algorithm != testFallbackPolicy.Algorithm {
+		t.Errorf("search policy = %+v, want window and algorithm of the fallback", search)
+	}
+	if !IsRateLimitExempt("/v1/jokes", "10.1.2.3") || IsRateLimitExempt("/v1/jokes", "192.168.1.1") {
+		t.Error("IsRateLimitExempt does not follow exempt_networks")
+	}
+	if !IsInternalNetwork("192.168.1.1") || IsInternalNetwork("10.1.2.3") {
+		t.Error("IsInternalNetwork does not follow internal_networks")
+	}
+}
+
+func TestLoadRateLimitPolicyRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"malformed":         `{"policies": [`,
+		"missing name":      `{"policies": [{"max_requests": 1}]}`,
+		"reserved name":     `{"policies": [{"name": "default", "max_requests": 1}]}`,
+		"duplicate name":    `{"policies": [{"name": "a", "max_requests": 1}, {"name": "a", "max_requests": 2}]}`,
+		"unknown tier":      `{"policies": [{"name": "a", "tiers": ["vip"], "max_requests": 1}]}`,
+		"no max requests":   `{"policies": [{"name": "a"}]}`,
+		"invalid window":    `{"policies": [{"name": "a", "max_requests": 1, "window": "soon"}]}`,
+		"invalid network":   `{"exempt_networks": ["10.0.0.0/33"]}`,
+		"invalid internals": `{"internal_networks": ["intranet"]}`,
+	}
+
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := loadTestRateLimitPolicy(t, policy); !errors.Is(err, ErrInvalidRateLimitPolicy) {
+				t.Fatalf("LoadRateLimitPolicy error = %v, want %v", err, ErrInvalidRateLimitPolicy)
+			}
+		})
+	}
+}
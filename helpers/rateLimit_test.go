@@ -0,0 +1,157 @@
+package helpers
+
+import (
+	"context"
+	"jokes-provider/utils"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// mapStore is an in-process fiber.Storage, like the memory fallback
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: map[string][]byte{}}
+}
+
+func (s *mapStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[key], nil
+}
+
+func (s *mapStore) Set(key string, val []byte, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = val
+	return nil
+}
+
+func (s *mapStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *mapStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = map[string][]byte{}
+	return nil
+}
+
+func (s *mapStore) Close() error { return nil }
+
+// redisScriptStore runs the rate limit scripts on a test Redis
+type redisScriptStore struct {
+	*mapStore
+	client *redis.Client
+	prefix string
+}
+
+func (s *redisScriptStore) RunScript(script *redis.Script, key string, args ...any) ([]int64, bool, error) {
+	reply, err := script.Run(context.Background(), s.client, []string{s.prefix + key}, args...).Int64Slice()
+	return reply, true, err
+}
+
+// newTestRedisStore connects to TEST_REDIS_URL or a local Redis, if one answers
+func newTestRedisStore(t *testing.T) *redisScriptStore {
+	t.Helper()
+
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		url = "redis://127.0.0.1:6379/15"
+	}
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(options)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("Redis not available at %s: %v", url, err)
+	}
+
+	prefix := "test:" + utils.CacheKeyPrefixRateLimit + strconv.FormatInt(time.Now().UnixNano(), 36) + ":"
+	t.Cleanup(func() {
+		keys, _ := client.Keys(context.Background(), prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(context.Background(), keys...)
+		}
+	})
+	return &redisScriptStore{mapStore: newMapStore(), client: client, prefix: prefix}
+}
+
+// TestRateLimiterStoresAgree checks the Redis scripts decide like the in-process algorithms
+func TestRateLimiterStoresAgree(t *testing.T) {
+	const window = time.Minute
+	algorithms := []string{
+		utils.RateLimitFixedWindow,
+		utils.RateLimitSlidingWindow,
+		utils.RateLimitSlidingLog,
+		utils.RateLimitTokenBucket,
+	}
+	stores := map[string]func(t *testing.T) fiber.Storage{
+		"memory": func(*testing.T) fiber.Storage { return newMapStore() },
+		"redis":  func(t *testing.T) fiber.Storage { return newTestRedisStore(t) },
+	}
+
+	for storeName, newStore := range stores {
+		for _, algorithm := range algorithms {
+			t.Run(storeName+"/"+algorithm, func(t *testing.T) {
+				store := newStore(t)
+				limiter, err := NewRateLimiter(algorithm, 3, window, 0, store)
+				if err != nil {
+					t.Fatalf("NewRateLimiter: %v", err)
+				}
+
+				// Denied requests must not use up the limit of the next window
+				wantAllowed := []bool{true, true, true, false, false, false}
+				wantRemaining := []int{2, 1, 0, 0, 0, 0}
+				now := time.Now()
+				for i := range wantAllowed {
+					decision, err := limiter.Take("client", now)
+					if err != nil {
+						t.Fatalf("Take %d: %v", i+1, err)
+					}
+					if decision.Allowed != wantAllowed[i] || decision.Remaining != wantRemaining[i] || decision.Limit != 3 {
+						t.Fatalf("Take %d = %+v, want allowed %v with %d remaining", i+1, decision, wantAllowed[i], wantRemaining[i])
+					}
+					if !decision.Allowed && (decision.RetryAfter <= 0 || decision.RetryAfter > 2*window) {
+						t.Errorf("Take %d retry after %s, want within %s", i+1, decision.RetryAfter, 2*window)
+					}
+				}
+
+				if redisStore, ok := store.(*redisScriptStore); ok && algorithm == utils.RateLimitFixedWindow {
+					count, err := redisStore.client.Get(context.Background(), redisStore.prefix+algorithm+":client").Int()
+					if err != nil || count != 3 {
+						t.Errorf("stored count = %d (%v), want 3", count, err)
+					}
+					// The Redis fixed window ends with the key's expiry, on the server's clock
+					return
+				}
+
+				decision, err := limiter.Take("client", now.Add(2*window+time.Millisecond))
+				if err != nil {
+					t.Fatalf("Take after the window: %v", err)
+				}
+				if !decision.Allowed || decision.Remaining != 2 {
+					t.Errorf("Take after the window = %+v, want allowed with 2 remaining", decision)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,607 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"html/template"
+	"jokes-provider/utils"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Responses are rendered from their JSON form, so every format shows the same fields
+
+// treeField is one field of a JSON object, kept in document order
+type treeField struct {
+	key   string
+	value any
+}
+
+// treeObject is a JSON object, kept in document order
+type treeObject []treeField
+
+// flatField is a leaf of a response, named by its path, such as "cache.ttl"
+type flatField struct {
+	Key   string
+	Value string
+}
+
+// RenderText renders the jokes of a response one per paragraph, or its fields as lines
+func RenderText(v any) ([]byte, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if jokes := collectJokes(tree); len(jokes) > 0 {
+		for i, joke := range jokes {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			text, _ := jokeText(joke)
+			buf.WriteString(text + "\n")
+		}
+		return buf.Bytes(), nil
+	}
+
+	for _, field := range flatten("", tree, nil) {
+		buf.WriteString(field.Key + ": " + field.Value + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderCSV renders the jokes or the list items of a response one per row,
+// or any other response as key and value rows
+func RenderCSV(v any) ([]byte, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []treeObject
+	if jokes := collectJokes(tree); len(jokes) > 0 {
+		rows = jokes
+	} else {
+		rows = topLevelRows(tree)
+	}
+
+	var records [][]string
+	if rows != nil {
+		records = tableRecords(rows)
+	} else if columns := declaredColumns(v); columns != nil {
+		records = [][]string{orderColumns(columns)}
+	} else {
+		records = [][]string{{"key", "value"}}
+		for _, field := range flatten("", tree, nil) {
+			records = append(records, []string{field.Key, field.Value})
+		}
+	}
+	for _, record := range records {
+		for i, cell := range record {
+			record[i] = csvCell(cell)
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderXML renders a response as XML under a root element
+func RenderXML(root string, v any) ([]byte, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	name := xmlName(root)
+	if err := encodeXML(encoder, name, xmlItemName(name), tree); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// RenderYAML renders a response as a YAML document
+func RenderYAML(v any) ([]byte, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlNode(tree)); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// htmlCard is a joke shown as a card
+type htmlCard struct {
+	Text    string
+	Details []flatField
+}
+
+var htmlPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+.joke { border: 1px solid #ddd; border-radius: 0.5rem; padding: 1rem 1.25rem; margin-bottom: 1rem; }
+.joke p { font-size: 1.25rem; margin: 0 0 0.75rem; }
+.joke footer { color: #666; font-size: 0.875rem; }
+.joke footer span + span::before { content: " · "; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: 0.25rem 1rem; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+</style>
+</head>
+<body>
+{{- range .Cards}}
+<article class="joke">
+<p>{{.Text}}</p>
+<footer>{{range .Details}}<span>{{.Key}}: {{.Value}}</span>{{end}}</footer>
+</article>
+{{- end}}
+{{- if .Fields}}
+<dl>
+{{- range .Fields}}
+<dt>{{.Key}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+</body>
+</html>
+`))
+
+// RenderHTML renders the jokes of a response as cards, or its fields as a list
+func RenderHTML(title string, v any) ([]byte, error) {
+	tree, err := toTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	page := struct {
+		Title  string
+		Cards  []htmlCard
+		Fields []flatField
+	}{Title: title}
+
+	if jokes := collectJokes(tree); len(jokes) > 0 {
+		for _, joke := range jokes {
+			card := htmlCard{}
+			for _, field := range joke {
+				if text, ok := field.value.(string); ok && strings.EqualFold(field.key, "joke") {
+					card.Text = text
+					continue
+				}
+				card.Details = flatten(field.key, field.value, card.Details)
+			}
+			page.Cards = append(page.Cards, card)
+		}
+	} else {
+		page.Fields = flatten("", tree, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlPage.Execute(&buf, page); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toTree converts v to its JSON form, keeping the order of object fields
+func toTree(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeTree(decoder)
+}
+
+func decodeTree(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := treeObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, treeField{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+	return token, nil
+}
+
+// jokeText returns the text of an object with a joke field
+func jokeText(object treeObject) (string, bool) {
+	for _, field := range object {
+		if text, ok := field.value.(string); ok && strings.EqualFold(field.key, "joke") {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// collectJokes returns the jokes found in a response, in document order
+func collectJokes(node any) []treeObject {
+	switch node := node.(type) {
+	case treeObject:
+		if _, ok := jokeText(node); ok {
+			return []treeObject{node}
+		}
+		var jokes []treeObject
+		for _, field := range node {
+			jokes = append(jokes, collectJokes(field.value)...)
+		}
+		return jokes
+	case []any:
+		var jokes []treeObject
+		for _, item := range node {
+			jokes = append(jokes, collectJokes(item)...)
+		}
+		return jokes
+	}
+	return nil
+}
+
+// topLevelRows returns the items of the first list of objects in a response
+func topLevelRows(tree any) []treeObject {
+	object, ok := tree.(treeObject)
+	if !ok {
+		return nil
+	}
+
+	for _, field := range object {
+		items, ok := field.value.([]any)
+		if !ok || len(items) == 0 {
+			continue
+		}
+		rows := make([]treeObject, 0, len(items))
+		for _, item := range items {
+			row, ok := item.(treeObject)
+			if !ok {
+				break
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == len(items) {
+			return rows
+		}
+	}
+	return nil
+}
+
+// tableRecords lays rows out as CSV records under a header row
+func tableRecords(rows []treeObject) [][]string {
+	flatRows := make([]map[string]string, len(rows))
+	var columns []string
+	seen := map[string]bool{}
+	for i, row := range rows {
+		flatRows[i] = map[string]string{}
+		for _, field := range flatten("", row, nil) {
+			flatRows[i][field.Key] = field.Value
+			if !seen[field.Key] {
+				seen[field.Key] = true
+				columns = append(columns, field.Key)
+			}
+		}
+	}
+
+	ordered := orderColumns(columns)
+	records := [][]string{ordered}
+	for _, row := range flatRows {
+		record := make([]string, len(ordered))
+		for i, column := range ordered {
+			record[i] = row[column]
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// orderColumns puts the id and joke columns first, the others in their order
+func orderColumns(columns []string) []string {
+	rank := func(column string) int {
+		switch strings.ToLower(column) {
+		case "id":
+			return 0
+		case "joke":
+			return 1
+		}
+		return 2
+	}
+	ordered := make([]string, 0, len(columns))
+	for r := 0; r <= 2; r++ {
+		for _, column := range columns {
+			if rank(column) == r {
+				ordered = append(ordered, column)
+			}
+		}
+	}
+	return ordered
+}
+
+// declaredColumns returns the columns of the first list of a response whose lists are all empty
+func declaredColumns(v any) []string {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var columns []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.Slice || !value.Type().Field(i).IsExported() {
+			continue
+		}
+		if field.Len() > 0 {
+			return nil
+		}
+		elem := field.Type().Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if columns == nil && elem.Kind() == reflect.Struct {
+			columns = typeColumns("", jokeType(elem), nil)
+		}
+	}
+	return columns
+}
+
+// jokeType returns the struct type of the jokes held by t, or t itself
+func jokeType(t reflect.Type) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && field.Type.Kind() == reflect.Struct && hasJokeField(field.Type) {
+			return field.Type
+		}
+	}
+	return t
+}
+
+func hasJokeField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _ := jsonFieldName(field); field.Type.Kind() == reflect.String && strings.EqualFold(name, "joke") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeColumns appends the scalar fields of the struct type t, named by their JSON path
+func typeColumns(path string, t reflect.Type, columns []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			columns = typeColumns(path, fieldType, columns)
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		switch {
+		case fieldType.Implements(jsonMarshalerType) || reflect.PointerTo(fieldType).Implements(jsonMarshalerType):
+			columns = append(columns, name)
+		case fieldType.Kind() == reflect.Struct:
+			columns = typeColumns(name, fieldType, columns)
+		case fieldType.Kind() == reflect.Map, fieldType.Kind() == reflect.Slice, fieldType.Kind() == reflect.Array:
+		default:
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+var jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+
+// jsonFieldName returns the JSON name of a field, or false if it is left out
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// csvCell keeps spreadsheets from evaluating a cell as a formula
+func csvCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// flatten appends the leaves of node to fields, named by their path
+func flatten(path string, node any, fields []flatField) []flatField {
+	switch node := node.(type) {
+	case treeObject:
+		for _, field := range node {
+			key := field.key
+			if path != "" {
+				key = path + "." + field.key
+			}
+			fields = flatten(key, field.value, fields)
+		}
+		return fields
+	case []any:
+		if len(node) == 0 {
+			return append(fields, flatField{Key: path})
+		}
+		for i, item := range node {
+			fields = flatten(path+"["+strconv.Itoa(i)+"]", item, fields)
+		}
+		return fields
+	}
+	return append(fields, flatField{Key: path, Value: scalarString(node)})
+}
+
+func scalarString(node any) string {
+	switch node := node.(type) {
+	case string:
+		return node
+	case json.Number:
+		return node.String()
+	case bool:
+		return strconv.FormatBool(node)
+	}
+	return ""
+}
+
+// encodeXML writes node as the element name, with list items as item elements
+func encodeXML(encoder *xml.Encoder, name, item string, node any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch node := node.(type) {
+	case treeObject:
+		for _, field := range node {
+			fieldName := xmlName(field.key)
+			fieldItem := xmlItemName(fieldName)
+			if field.key == utils.JSONKeyData {
+				// A data envelope holds the items of its parent: "jokes" > "data" > "joke"
+				fieldItem = item
+			}
+			if err := encodeXML(encoder, fieldName, fieldItem, field.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range node {
+			if err := encodeXML(encoder, item, xmlItemName(item), value); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := encoder.EncodeToken(xml.CharData(scalarString(node))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlName makes a field name, which may come from a CSV header, a valid element name
+func xmlName(key string) string {
+	name := []rune(key)
+	for i, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || !(unicode.IsLetter(name[0]) || name[0] == '_') {
+		name = append([]rune{'_'}, name...)
+	}
+	return string(name)
+}
+
+// xmlItemName names the items of a list after it, "jokes" holding "joke" elements
+func xmlItemName(list string) string {
+	switch {
+	case strings.HasSuffix(list, "ies"):
+		return strings.TrimSuffix(list, "ies") + "y"
+	case strings.HasSuffix(list, "s") && !strings.HasSuffix(list, "ss"):
+		return strings.TrimSuffix(list, "s")
+	}
+	return "item"
+}
+
+func yamlNode(node any) *yaml.Node {
+	switch node := node.(type) {
+	case treeObject:
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for _, field := range node {
+			mapping.Content = append(mapping.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.key},
+				yamlNode(field.value),
+			)
+		}
+		return mapping
+	case []any:
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range node {
+			sequence.Content = append(sequence.Content, yamlNode(item))
+		}
+		return sequence
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: node}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(node.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: node.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(node)}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
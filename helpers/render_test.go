@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"jokes-provider/models"
+	"testing"
+)
+
+func TestRenderCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			name: "jokes",
+			v:    models.JokeList{Data: []models.Joke{{ID: "1", Joke: "plain", Category: "pun"}}, Total: 1, Limit: 10},
+			want: "id,joke,category\n1,plain,pun\n",
+		},
+		{
+			name: "formula cells",
+			v: models.JokeList{Data: []models.Joke{
+				{ID: "1", Joke: "=HYPERLINK(\"http://evil\")"},
+				{ID: "2", Joke: "+1", Category: "-2"},
+				{ID: "3", Joke: "@SUM(A1)", Category: "a=b"},
+				{ID: "4", Joke: "\tTAB"},
+			}},
+			want: "id,joke,category\n1,\"'=HYPERLINK(\"\"http://evil\"\")\",\n2,'+1,'-2\n3,'@SUM(A1),a=b\n4,'\tTAB,\n",
+		},
+		{
+			name: "empty search",
+			v:    models.SearchResponse{Query: "nothing", Limit: 10, Results: []models.SearchResult{}},
+			want: "id,joke,category\n",
+		},
+		{
+			name: "empty jokes list",
+			v:    &models.JokeList{Limit: 10},
+			want: "id,joke,category\n",
+		},
+		{
+			name: "empty categories",
+			v:    models.CategoryList{},
+			want: "name,count\n",
+		},
+		{
+			name: "empty list next to other items",
+			v:    models.BatchResponse{Jokes: []models.Joke{}, Missing: []string{"7"}},
+			want: "key,value\njokes,\nmissing[0],7\n",
+		},
+		{
+			name: "categories",
+			v:    models.CategoryList{Categories: []models.Category{{Name: "pun", Count: 2}}, Total: 1},
+			want: "name,count\npun,2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderCSV(tt.v)
+			if err != nil {
+				t.Fatalf("RenderCSV error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("RenderCSV = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
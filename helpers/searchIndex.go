@@ -0,0 +1,299 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 ranking parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// minPrefixLength avoids expanding very short prefixes to most of the vocabulary
+	minPrefixLength = 2
+)
+
+// stopWords are skipped when indexing and querying but still count as positions
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "if": true, "in": true,
+	"into": true, "is": true, "it": true, "no": true, "not": true, "of": true,
+	"on": true, "or": true, "so": true, "such": true, "that": true, "the": true,
+	"their": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "was": true, "will": true, "with": true,
+}
+
+// token is a normalized word and its position in the source text
+type token struct {
+	term     string
+	position int
+}
+
+// tokenize lowercases text and splits it into words, dropping apostrophes
+func tokenize(text string) []token {
+	words := tokenizeWords(text)
+	tokens := words[:0]
+	for _, tok := range words {
+		if !stopWords[tok.term] {
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens
+}
+
+// tokenizeWords lowercases text and splits it into words, keeping stop words
+func tokenizeWords(text string) []token {
+	var tokens []token
+	var current strings.Builder
+	position := 0
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		tokens = append(tokens, token{term: current.String(), position: position})
+		current.Reset()
+		position++
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// Keep contractions together
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// posting lists the positions of a term within one joke
+type posting struct {
+	doc       int
+	positions []int
+}
+
+// searchIndex is an inverted index over joke text
+type searchIndex struct {
+	postings     map[string][]posting
+	terms        []string
+	docLengths   []int
+	avgDocLength float64
+}
+
+// newSearchIndex indexes the given texts; document numbers are slice indices
+func newSearchIndex(texts []string) *searchIndex {
+	index := &searchIndex{
+		postings:   make(map[string][]posting),
+		docLengths: make([]int, len(texts)),
+	}
+
+	totalLength := 0
+	for doc, text := range texts {
+		tokens := tokenize(text)
+		index.docLengths[doc] = len(tokens)
+		totalLength += len(tokens)
+
+		positions := make(map[string][]int)
+		var order []string
+		for _, tok := range tokens {
+			if _, seen := positions[tok.term]; !seen {
+				order = append(order, tok.term)
+			}
+			positions[tok.term] = append(positions[tok.term], tok.position)
+		}
+
+		for _, term := range order {
+			index.postings[term] = append(index.postings[term], posting{doc: doc, positions: positions[term]})
+		}
+	}
+
+	if len(texts) > 0 {
+		index.avgDocLength = float64(totalLength) / float64(len(texts))
+	}
+
+	index.terms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+
+	return index
+}
+
+// SearchHit is a matching joke index with its relevance score
+type SearchHit struct {
+	Doc   int
+	Score float64
+}
+
+// searchClause is one part of a parsed query: a word, a prefix or a phrase
+type searchClause struct {
+	terms   []string
+	offsets []int
+	prefix  bool
+}
+
+// parseSearchQuery splits a query into phrase, prefix and plain term clauses.
+// Stop words are kept as prefixes, since "the*" matches "theory".
+func parseSearchQuery(query string) []searchClause {
+	var clauses []searchClause
+
+	parts := strings.Split(query, "\"")
+	for i, part := range parts {
+		// Odd segments sit between quotes
+		if i%2 == 1 {
+			tokens := tokenize(part)
+			if len(tokens) == 0 {
+				continue
+			}
+			clause := searchClause{}
+			for _, tok := range tokens {
+				clause.terms = append(clause.terms, tok.term)
+				clause.offsets = append(clause.offsets, tok.position-tokens[0].position)
+			}
+			clauses = append(clauses, clause)
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			tokens := tokenizeWords(strings.TrimSuffix(word, "*"))
+			for i, tok := range tokens {
+				// Only the last word of "half-bak*" is a prefix
+				prefix := strings.HasSuffix(word, "*") && i == len(tokens)-1
+				if (prefix && len(tok.term) < minPrefixLength) || (!prefix && stopWords[tok.term]) {
+					continue
+				}
+				clauses = append(clauses, searchClause{terms: []string{tok.term}, offsets: []int{0}, prefix: prefix})
+			}
+		}
+	}
+
+	return clauses
+}
+
+// search scores the documents matching any clause, or every one with matchAll, with BM25
+func (idx *searchIndex) search(query string, matchAll bool) []SearchHit {
+	clauses := parseSearchQuery(query)
+	if len(clauses) == 0 {
+		return []SearchHit{}
+	}
+
+	scores := make(map[int]float64)
+	matches := make(map[int]int)
+
+	for _, clause := range clauses {
+		var clauseScores map[int]float64
+		switch {
+		case len(clause.terms) > 1:
+			clauseScores = idx.scorePhrase(clause)
+		case clause.prefix:
+			clauseScores = idx.scorePrefix(clause.terms[0])
+		default:
+			clauseScores = idx.scoreTerm(clause.terms[0])
+		}
+
+		for doc, score := range clauseScores {
+			scores[doc] += score
+			matches[doc]++
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for doc, score := range scores {
+		if matchAll && matches[doc] < len(clauses) {
+			continue
+		}
+		hits = append(hits, SearchHit{Doc: doc, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc < hits[j].Doc
+	})
+
+	return hits
+}
+
+// bm25 scores a term occurring termFrequency times in doc
+func (idx *searchIndex) bm25(term string, doc int, termFrequency int) float64 {
+	docCount := float64(len(idx.docLengths))
+	docFrequency := float64(len(idx.postings[term]))
+	idf := math.Log(1 + (docCount-docFrequency+0.5)/(docFrequency+0.5))
+
+	tf := float64(termFrequency)
+	norm := 1 - bm25B + bm25B*float64(idx.docLengths[doc])/idx.avgDocLength
+	return idf * (tf * (bm25K1 + 1)) / (tf + bm25K1*norm)
+}
+
+func (idx *searchIndex) scoreTerm(term string) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, p := range idx.postings[term] {
+		scores[p.doc] = idx.bm25(term, p.doc, len(p.positions))
+	}
+	return scores
+}
+
+// scorePrefix keeps, per document, the best score among all terms with the prefix
+func (idx *searchIndex) scorePrefix(prefix string) map[int]float64 {
+	scores := make(map[int]float64)
+	start := sort.SearchStrings(idx.terms, prefix)
+	for i := start; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		for doc, score := range idx.scoreTerm(idx.terms[i]) {
+			if score > scores[doc] {
+				scores[doc] = score
+			}
+		}
+	}
+	return scores
+}
+
+// scorePhrase matches documents containing the terms at the expected relative positions
+func (idx *searchIndex) scorePhrase(clause searchClause) map[int]float64 {
+	lists := make([]map[int][]int, len(clause.terms))
+	for i, term := range clause.terms {
+		lists[i] = make(map[int][]int, len(idx.postings[term]))
+		for _, p := range idx.postings[term] {
+			lists[i][p.doc] = p.positions
+		}
+	}
+
+	scores := make(map[int]float64)
+	for doc, starts := range lists[0] {
+		if !phraseMatches(lists, doc, starts, clause.offsets) {
+			continue
+		}
+		for i, term := range clause.terms {
+			scores[doc] += idx.bm25(term, doc, len(lists[i][doc]))
+		}
+	}
+	return scores
+}
+
+func phraseMatches(lists []map[int][]int, doc int, starts []int, offsets []int) bool {
+	for _, start := range starts {
+		matched := true
+		for i := 1; i < len(lists) && matched; i++ {
+			matched = containsInt(lists[i][doc], start+offsets[i])
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt reports whether the sorted slice contains value
+func containsInt(sorted []int, value int) bool {
+	i := sort.SearchInts(sorted, value)
+	return i < len(sorted) && sorted[i] == value
+}
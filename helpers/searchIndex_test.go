@@ -0,0 +1,161 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []searchClause
+	}{
+		{
+			name:  "terms drop stop words",
+			query: "the cat and dog",
+			want: []searchClause{
+				{terms: []string{"cat"}, offsets: []int{0}},
+				{terms: []string{"dog"}, offsets: []int{0}},
+			},
+		},
+		{
+			name:  "phrase keeps offsets across stop words",
+			query: `"man walks into the bar"`,
+			want: []searchClause{
+				{terms: []string{"man", "walks", "bar"}, offsets: []int{0, 1, 4}},
+			},
+		},
+		{
+			name:  "phrase starting with a stop word",
+			query: `"the cat sat on a mat"`,
+			want: []searchClause{
+				{terms: []string{"cat", "sat", "mat"}, offsets: []int{0, 1, 4}},
+			},
+		},
+		{
+			name:  "phrase of one word is a term",
+			query: `"Cat"`,
+			want: []searchClause{
+				{terms: []string{"cat"}, offsets: []int{0}},
+			},
+		},
+		{
+			name:  "phrase of stop words only",
+			query: `"to be or not"`,
+			want:  nil,
+		},
+		{
+			name:  "prefix",
+			query: "dog*",
+			want: []searchClause{
+				{terms: []string{"dog"}, offsets: []int{0}, prefix: true},
+			},
+		},
+		{
+			name:  "stop word prefix",
+			query: "the*",
+			want: []searchClause{
+				{terms: []string{"the"}, offsets: []int{0}, prefix: true},
+			},
+		},
+		{
+			name:  "short prefix is dropped",
+			query: "d* cat",
+			want: []searchClause{
+				{terms: []string{"cat"}, offsets: []int{0}},
+			},
+		},
+		{
+			name:  "only the last word of a hyphenated prefix",
+			query: "half-bak*",
+			want: []searchClause{
+				{terms: []string{"half"}, offsets: []int{0}},
+				{terms: []string{"bak"}, offsets: []int{0}, prefix: true},
+			},
+		},
+		{
+			name:  "phrase and terms",
+			query: `knock "who's there" door*`,
+			want: []searchClause{
+				{terms: []string{"knock"}, offsets: []int{0}},
+				{terms: []string{"whos"}, offsets: []int{0}},
+				{terms: []string{"door"}, offsets: []int{0}, prefix: true},
+			},
+		},
+		{
+			name:  "unterminated quote",
+			query: `"space cats`,
+			want: []searchClause{
+				{terms: []string{"space", "cats"}, offsets: []int{0, 1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSearchQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	index := newSearchIndex([]string{
+		0: "A man walks into the bar",
+		1: "A man walks into a bar and orders a drink",
+		2: "The man walks, bar closed",
+		3: "My theory about cats",
+		4: "Cats and dogs living together",
+		5: "Dogmatic dogs",
+	})
+
+	tests := []struct {
+		name     string
+		query    string
+		matchAll bool
+		want     []int
+	}{
+		{"phrase across stop words", `"walks into the bar"`, false, []int{0, 1}},
+		{"phrase needs the same spacing", `"walks bar"`, false, []int{2}},
+		{"prefix expands", "dog*", false, []int{4, 5}},
+		{"stop word prefix expands", "the*", false, []int{3}},
+		{"any clause", "theory dogs", false, []int{3, 4, 5}},
+		{"all clauses", "cats dogs", true, []int{4}},
+		{"all clauses with a prefix", "cat* theo*", true, []int{3}},
+		{"all clauses unmatched", "cats drink", true, []int{}},
+		{"stop words only", "the and", false, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := index.search(tt.query, tt.matchAll)
+			got := make(map[int]bool, len(hits))
+			for _, hit := range hits {
+				got[hit.Doc] = true
+			}
+
+			want := make(map[int]bool, len(tt.want))
+			for _, doc := range tt.want {
+				want[doc] = true
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("search(%q, %v) matched %v, want %v", tt.query, tt.matchAll, got, want)
+			}
+		})
+	}
+}
+
+func TestSearchIndexRanksByRelevance(t *testing.T) {
+	index := newSearchIndex([]string{
+		0: "a long joke that mentions a duck once among many other words",
+		1: "duck duck duck",
+	})
+
+	hits := index.search("duck", false)
+	if len(hits) != 2 || hits[0].Doc != 1 {
+		t.Errorf("hits = %+v, want the repeated term first", hits)
+	}
+}
@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"jokes-provider/config"
+	"jokes-provider/middleware"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionDeckKey builds the store key of a session's deck from a hash of the session ID
+func SessionDeckKey(sessionID string, categories []string) string {
+	hash := sha256.Sum256([]byte(sessionID))
+	key := utils.CacheKeyPrefixSession + hex.EncodeToString(hash[:8])
+
+	if len(categories) > 0 {
+		key += ":" + utils.QueryCategory + ":" + CategoryKey(categories)
+	}
+
+	return key
+}
+
+// LoadSessionDeck returns the stored deck, or an empty one
+func LoadSessionDeck(c *fiber.Ctx, key string) models.SessionDeck {
+	var deck models.SessionDeck
+
+	data, err := middleware.GetSessionStore().Get(key)
+	if err != nil {
+		config.LogError(c, "Error loading session deck", "session_key", key, "error", err.Error())
+		return deck
+	}
+
+	if data == nil {
+		return deck
+	}
+
+	if err := json.Unmarshal(data, &deck); err != nil {
+		config.LogError(c, "Error unmarshaling session deck", "session_key", key, "error", err.Error())
+		return models.SessionDeck{}
+	}
+
+	return deck
+}
+
+// SaveSessionDeck stores the deck and refreshes its TTL
+func SaveSessionDeck(c *fiber.Ctx, key string, deck models.SessionDeck) error {
+	data, err := json.Marshal(deck)
+	if err != nil {
+		return err
+	}
+
+	ttl := utils.GetDurationFromEnv(config.AppConfig.SessionTTL, 30*time.Minute)
+	if err := middleware.GetSessionStore().Set(key, data, ttl); err != nil {
+		config.LogError(c, "Error saving session deck", "session_key", key, "error", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"jokes-provider/api"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 // @title Jokes Provider API
@@ -23,6 +25,15 @@ import (
 
 // @securityDefinitions.basic  BasicAuth
 
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT bearer token, sent as "Bearer <token>"
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
@@ -33,12 +44,26 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Graceful shutdown
-	defer api.Shutdown()
+	// Start the server; Listen blocks until the server stops
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- api.Start(app)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start the server
-	if err := api.Start(app); err != nil {
+	select {
+	case err := <-serverErr:
 		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 		os.Exit(1)
+	case <-signals:
+		// A second signal skips the graceful shutdown
+		signal.Stop(signals)
+	}
+
+	// Graceful shutdown
+	if err := api.Shutdown(app); err != nil {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"errors"
+	"jokes-provider/problems"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "jokes_provider"
+
+// Cache operation results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheOK    = "ok"
+	CacheError = "error"
+	// CacheBypass counts operations skipped while the circuit breaker is open
+	CacheBypass = "bypass"
+)
+
+// routeUnmatched labels requests to unknown paths, which would make the label set unbounded
+const routeUnmatched = "unmatched"
+
+// Registry holds the service metrics along with the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route pattern and status code.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method", "route", "status"})
+
+	cacheOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_operations_total",
+		Help:      "Redis cache operations by operation (get, set, delete) and result (hit, miss, ok, error, bypass).",
+	}, []string{"operation", "result"})
+
+	rateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter, by policy and client tier.",
+	}, []string{"policy", "tier"})
+
+	datasetJokes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dataset_jokes",
+		Help:      "Number of jokes in the loaded dataset.",
+	})
+
+	datasetLoaded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dataset_last_load_timestamp_seconds",
+		Help:      "Unix time the dataset was last loaded, reloaded or changed through the admin API.",
+	})
+
+	datasetReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dataset_reloads_total",
+		Help:      "Dataset reloads by result (success, failure).",
+	}, []string{"result"})
+
+	redisHealthChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_health_checks_total",
+		Help:      "Redis health checks by result (up, down).",
+	}, []string{"result"})
+
+	redisUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "redis_up",
+		Help:      "Whether the last Redis health check passed (1) or failed (0).",
+	})
+
+	cacheBreakerOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_breaker_open",
+		Help:      "Whether the Redis circuit breaker is open (1), bypassing the cache, or closed (0).",
+	})
+
+	cacheBreakerTrips = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_breaker_trips_total",
+		Help:      "Times the Redis circuit breaker opened.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, cacheOperations, rateLimitRejections,
+		datasetJokes, datasetLoaded, datasetReloads, redisHealthChecks, redisUp,
+		cacheBreakerOpen, cacheBreakerTrips,
+	)
+}
+
+// Middleware records the count and latency of requests by route pattern, except for skipPath
+func Middleware(skipPath string) fiber.Handler {
+	// Routes are registered after the middleware
+	var once sync.Once
+	var routes map[string][]string
+
+	return func(c *fiber.Ctx) error {
+		if c.Path() == skipPath {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		// The error handler sets the status after the middleware returns
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = problems.StatusCode(err)
+		}
+
+		// A request stopped by middleware, such as a 406, has only matched its prefix
+		once.Do(func() { routes = handlerRoutes(c.App()) })
+		route := leafRoute(c, routes[c.Method()], c.Route().Path)
+		// Global middleware is mounted on "/"
+		if route == "/" && c.Path() != "/" {
+			route = routeUnmatched
+		}
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && (status == fiber.StatusNotFound || status == fiber.StatusMethodNotAllowed) {
+			route = routeUnmatched
+		}
+
+		labels := prometheus.Labels{"method": c.Method(), "route": route, "status": strconv.Itoa(status)}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// handlerRoutes returns the patterns of the routes other than middleware, by method
+func handlerRoutes(app *fiber.App) map[string][]string {
+	routes := map[string][]string{}
+	for _, r := range app.GetRoutes(true) {
+		routes[r.Method] = append(routes[r.Method], r.Path)
+	}
+	return routes
+}
+
+// leafRoute returns the first of patterns matching the request, unless route is one of them
+func leafRoute(c *fiber.Ctx, patterns []string, route string) string {
+	if slices.Contains(patterns, route) {
+		return route
+	}
+	for _, pattern := range patterns {
+		if fiber.RoutePatternMatch(c.Path(), pattern, c.App().Config()) {
+			return pattern
+		}
+	}
+	return route
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(HTTPHandler())
+}
+
+// HTTPHandler serves the metrics for a standalone net/http server
+func HTTPHandler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveCache counts a cache operation and its result
+func ObserveCache(operation, result string) {
+	cacheOperations.WithLabelValues(operation, result).Inc()
+}
+
+// ObserveRateLimitRejection counts a request rejected by the rate limiter
+func ObserveRateLimitRejection(policy, tier string) {
+	rateLimitRejections.WithLabelValues(policy, tier).Inc()
+}
+
+// ObserveDatasetLoad records the size and time of a dataset (re)load
+func ObserveDatasetLoad(jokes int, at time.Time) {
+	datasetJokes.Set(float64(jokes))
+	datasetLoaded.Set(float64(at.Unix()))
+}
+
+// ObserveDatasetReload counts a watcher reload attempt
+func ObserveDatasetReload(success bool) {
+	if success {
+		datasetReloads.WithLabelValues("success").Inc()
+	} else {
+		datasetReloads.WithLabelValues("failure").Inc()
+	}
+}
+
+// ObserveRedisHealth records the outcome of a Redis health check
+func ObserveRedisHealth(up bool) {
+	if up {
+		redisHealthChecks.WithLabelValues("up").Inc()
+		redisUp.Set(1)
+	} else {
+		redisHealthChecks.WithLabelValues("down").Inc()
+		redisUp.Set(0)
+	}
+}
+
+// ObserveCacheBreaker records a Redis circuit breaker state change
+func ObserveCacheBreaker(open bool) {
+	if open {
+		cacheBreakerTrips.Inc()
+		cacheBreakerOpen.Set(1)
+	} else {
+		cacheBreakerOpen.Set(0)
+	}
+}
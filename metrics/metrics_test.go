@@ -0,0 +1,103 @@
+package metrics_test
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/metrics"
+	"jokes-provider/problems"
+	"jokes-provider/services"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// requestCount returns the http_requests_total sample with the given labels
+func requestCount(t *testing.T, method, route, status string) float64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	want := map[string]string{"method": method, "route": route, "status": status}
+	for _, family := range families {
+		if family.GetName() != "jokes_provider_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if labelsMatch(metric.GetLabel(), want) {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func labelsMatch(labels []*dto.LabelPair, want map[string]string) bool {
+	for _, label := range labels {
+		if want[label.GetName()] != label.GetValue() {
+			return false
+		}
+	}
+	return len(labels) == len(want)
+}
+
+func TestMain(m *testing.M) {
+	config.LoadEnvVars()
+	config.InitializeLogger(fiber.New())
+	os.Exit(m.Run())
+}
+
+func TestMiddlewareRouteLabel(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: problems.Handler})
+	app.Use(metrics.Middleware("/metrics"))
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Stop") != "" {
+			return problems.RateLimited(1)
+		}
+		return c.Next()
+	})
+	jokes := app.Group("/v1/jokes", services.NegotiateFormat())
+	jokes.Get("/", func(c *fiber.Ctx) error { return c.SendString("list") })
+	jokes.Get("/random", func(c *fiber.Ctx) error { return c.SendString("random") })
+	jokes.Get("/:id", func(c *fiber.Ctx) error { return c.SendString("joke") })
+	app.Get("/metrics", metrics.Handler())
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		route  string
+		status string
+	}{
+		{"handled", "/v1/jokes/random", "", "", "/v1/jokes/random", "200"},
+		{"not acceptable", "/v1/jokes/random", fiber.HeaderAccept, "image/png", "/v1/jokes/random", "406"},
+		{"not acceptable with parameter", "/v1/jokes/42", fiber.HeaderAccept, "image/png", "/v1/jokes/:id", "406"},
+		{"stopped by global middleware", "/v1/jokes/random", "X-Stop", "1", "/v1/jokes/random", "429"},
+		{"unknown path", "/v2/jokes", "", "", "unmatched", "404"},
+		{"unknown path stopped by global middleware", "/v2/jokes", "X-Stop", "1", "unmatched", "429"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if status := strconv.Itoa(resp.StatusCode); status != tt.status {
+				t.Fatalf("GET %s = %s, want %s", tt.path, status, tt.status)
+			}
+
+			if count := requestCount(t, fiber.MethodGet, tt.route, tt.status); count != 1 {
+				t.Errorf("requests labelled route=%q status=%s = %v, want 1", tt.route, tt.status, count)
+			}
+		})
+	}
+}
@@ -4,28 +4,73 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"jokes-provider/config"
+	"jokes-provider/metrics"
+	"jokes-provider/tracing"
 	"jokes-provider/utils"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/storage/redis"
 )
 
-var redisStore *redis.Storage
+// Cache operations, as labelled in metrics
+const (
+	cacheOpGet    = "get"
+	cacheOpSet    = "set"
+	cacheOpDelete = "delete"
+)
+
+// redisStore is nil until Redis has been reached
+var redisStore atomic.Pointer[redis.Storage]
+var memoryStore = NewMemoryStore()
 
+// InitRedis connects to Redis when caching is enabled, or opens the circuit breaker
 func InitRedis() error {
-	redisStore = redis.New(GetRedisConfig())
+	if !config.CacheConfig.CacheEnabled {
+		config.LogInfo(nil, "Cache is disabled, Redis is not used")
+		return nil
+	}
+
+	store, err := connectRedis()
+	if err != nil {
+		cacheBreaker.openWith(err)
+		return nil
+	}
+	setRedisStore(store)
 	return nil
 }
 
+// GetRedisStore returns the Redis connection, or nil if it was never established
 func GetRedisStore() *redis.Storage {
-	return redisStore
+	return redisStore.Load()
+}
+
+func setRedisStore(store *redis.Storage) {
+	redisStore.Store(store)
+}
+
+// IsRedisAvailable reports whether Redis is connected and the circuit breaker is closed
+func IsRedisAvailable() bool {
+	return availableRedis() != nil
+}
+
+// GetSessionStore returns Redis when caching is enabled, and a process-local store otherwise
+func GetSessionStore() fiber.Storage {
+	if !config.CacheConfig.CacheEnabled {
+		return memoryStore
+	}
+	if store := availableRedis(); store != nil {
+		return store
+	}
+	return memoryStore
 }
 
 func CloseRedis() error {
-	if redisStore != nil {
-		return redisStore.Close()
+	cacheBreaker.shutdown()
+	if store := GetRedisStore(); store != nil {
+		return store.Close()
 	}
 	return nil
 }
@@ -66,35 +111,90 @@ func GetRedisConfig() redis.Config {
 	return cfg
 }
 
+// GetFromCache reads key from Redis, missing while the circuit breaker is open
 func GetFromCache(c *fiber.Ctx, key string) ([]byte, error) {
-	store := GetRedisStore()
+	store := availableRedis()
+	if store == nil {
+		metrics.ObserveCache(cacheOpGet, metrics.CacheBypass)
+		config.LogDebug(c, "Cache bypassed", "cache_key", key, "breaker", utils.CacheBreakerOpen)
+		return nil, nil
+	}
+
+	span := tracing.StartRedis(c, "GET", key)
+	defer span.End()
 
 	val, err := store.Get(key)
+	observeRedis(err)
 	if err != nil {
+		span.Fail(err)
+		metrics.ObserveCache(cacheOpGet, metrics.CacheError)
 		config.LogError(c, "Error retrieving from cache", "cache_key", key, "error", err.Error())
 		return nil, err
 	}
 
+	span.SetAttributes(tracing.AttributeCacheHit.Bool(val != nil))
 	if val != nil {
+		metrics.ObserveCache(cacheOpGet, metrics.CacheHit)
 		config.LogInfo(c, "Cache hit", "cache_key", key)
 	} else {
+		metrics.ObserveCache(cacheOpGet, metrics.CacheMiss)
 		config.LogInfo(c, "Cache miss", "cache_key", key)
 	}
 
 	return val, nil
 }
 
+// SetToCache writes key to Redis, unless the circuit breaker is open
 func SetToCache(c *fiber.Ctx, key string, value []byte) error {
-	store := GetRedisStore()
+	store := availableRedis()
+	if store == nil {
+		metrics.ObserveCache(cacheOpSet, metrics.CacheBypass)
+		config.LogDebug(c, "Cache write bypassed", "cache_key", key, "breaker", utils.CacheBreakerOpen)
+		return nil
+	}
 
 	// Convert TTL string to time.Duration (supports 5m, 1h, 30s, etc.)
 	ttl := utils.GetDurationFromEnv(config.CacheConfig.CacheTTL, 5*time.Minute)
 
-	if err := store.Set(key, value, ttl); err != nil {
+	span := tracing.StartRedis(c, "SET", key)
+	defer span.End()
+
+	err := store.Set(key, value, ttl)
+	observeRedis(err)
+	if err != nil {
+		span.Fail(err)
+		metrics.ObserveCache(cacheOpSet, metrics.CacheError)
 		config.LogError(c, "Error setting cache", "cache_key", key, "ttl", config.CacheConfig.CacheTTL, "error", err.Error())
 		return err
 	}
 
+	metrics.ObserveCache(cacheOpSet, metrics.CacheOK)
 	config.LogInfo(c, "Cache set", "cache_key", key, "ttl", config.CacheConfig.CacheTTL)
 	return nil
 }
+
+// DeleteFromCache removes key from Redis, unless the circuit breaker is open
+func DeleteFromCache(c *fiber.Ctx, key string) error {
+	store := availableRedis()
+	if store == nil {
+		metrics.ObserveCache(cacheOpDelete, metrics.CacheBypass)
+		config.LogDebug(c, "Cache delete bypassed", "cache_key", key, "breaker", utils.CacheBreakerOpen)
+		return nil
+	}
+
+	span := tracing.StartRedis(c, "DEL", key)
+	defer span.End()
+
+	err := store.Delete(key)
+	observeRedis(err)
+	if err != nil {
+		span.Fail(err)
+		metrics.ObserveCache(cacheOpDelete, metrics.CacheError)
+		config.LogError(c, "Error deleting from cache", "cache_key", key, "error", err.Error())
+		return err
+	}
+
+	metrics.ObserveCache(cacheOpDelete, metrics.CacheOK)
+	config.LogInfo(c, "Cache deleted", "cache_key", key)
+	return nil
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"jokes-provider/config"
+	"jokes-provider/metrics"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"sync"
+	"time"
+
+	"github.com/gofiber/storage/redis"
+)
+
+// circuitBreaker keeps requests away from Redis after CACHE_BREAKER_THRESHOLD
+// consecutive failures, until a background probe gets an answer
+type circuitBreaker struct {
+	mu        sync.Mutex
+	open      bool
+	failures  int
+	openedAt  time.Time
+	lastError string
+	// stop ends the probe; it is set while the breaker is open
+	stop chan struct{}
+}
+
+var cacheBreaker = &circuitBreaker{}
+
+// availableRedis returns the Redis store, or nil while it cannot be used
+func availableRedis() *redis.Storage {
+	if cacheBreaker.isOpen() {
+		return nil
+	}
+	return GetRedisStore()
+}
+
+// observeRedis feeds the outcome of a Redis call to the breaker
+func observeRedis(err error) {
+	if err != nil {
+		cacheBreaker.failure(err)
+	} else {
+		cacheBreaker.success()
+	}
+}
+
+// GetCacheBreakerInfo describes the breaker state for the metadata endpoint
+func GetCacheBreakerInfo() models.CacheBreakerInfo {
+	if !config.CacheConfig.CacheEnabled {
+		return models.CacheBreakerInfo{State: utils.CacheBreakerDisabled}
+	}
+
+	b := cacheBreaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	info := models.CacheBreakerInfo{
+		State:               utils.CacheBreakerClosed,
+		ConsecutiveFailures: b.failures,
+		Threshold:           breakerThreshold(),
+		ProbeInterval:       breakerProbeInterval().String(),
+		LastError:           b.lastError,
+	}
+	if b.open {
+		info.State = utils.CacheBreakerOpen
+		info.OpenedAt = b.openedAt.Format(time.RFC3339)
+	}
+	return info
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		b.failures = 0
+	}
+}
+
+func (b *circuitBreaker) failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.failures >= breakerThreshold() {
+		b.trip()
+	}
+}
+
+// openWith opens the breaker after err regardless of the threshold
+func (b *circuitBreaker) openWith(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	b.trip()
+}
+
+// trip opens the breaker and starts the probe. The caller holds b.mu.
+func (b *circuitBreaker) trip() {
+	b.open = true
+	b.openedAt = time.Now()
+	b.stop = make(chan struct{})
+	metrics.ObserveCacheBreaker(true)
+	config.LogError(nil, "Redis circuit breaker opened, bypassing cache",
+		"breaker", utils.CacheBreakerOpen, "consecutive_failures", b.failures,
+		"probe_interval", breakerProbeInterval().String(), "error", b.lastError)
+
+	go b.probe(b.stop)
+}
+
+// probe pings Redis until it answers, then closes the breaker
+func (b *circuitBreaker) probe(stop chan struct{}) {
+	ticker := time.NewTicker(breakerProbeInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if err := pingRedis(); err != nil {
+			config.LogError(nil, "Redis probe failed, cache still bypassed", "breaker", utils.CacheBreakerOpen, "error", err.Error())
+			continue
+		}
+
+		b.mu.Lock()
+		select {
+		case <-stop:
+			// Stopped while pinging: Redis is being closed
+		default:
+			outage := time.Since(b.openedAt).Round(time.Second)
+			b.open = false
+			b.failures = 0
+			b.lastError = ""
+			b.stop = nil
+			metrics.ObserveCacheBreaker(false)
+			config.LogInfo(nil, "Redis circuit breaker closed, cache restored", "breaker", utils.CacheBreakerClosed, "outage", outage.String())
+		}
+		b.mu.Unlock()
+		return
+	}
+}
+
+// shutdown stops the probe, so it does not reconnect while Redis is closed
+func (b *circuitBreaker) shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+// pingRedis checks that Redis answers, connecting first if needed
+func pingRedis() error {
+	store := GetRedisStore()
+	if store == nil {
+		connected, err := connectRedis()
+		if err != nil {
+			return err
+		}
+		setRedisStore(connected)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), breakerProbeInterval())
+	defer cancel()
+	return store.Conn().Ping(ctx).Err()
+}
+
+// connectRedis opens the Redis connection, recovering the driver's panic when unreachable
+func connectRedis() (store *redis.Storage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("redis connection failed: %v", r)
+		}
+	}()
+	return redis.New(GetRedisConfig()), nil
+}
+
+func breakerThreshold() int {
+	return max(config.CacheConfig.CacheBreakerThreshold, 1)
+}
+
+func breakerProbeInterval() time.Duration {
+	interval := utils.GetDurationFromEnv(config.CacheConfig.CacheBreakerProbeInterval, 10*time.Second)
+	if interval <= 0 {
+		return 10 * time.Second
+	}
+	return interval
+}
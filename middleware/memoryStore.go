@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a process-local fiber.Storage used when Redis caching is disabled
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	sets    int
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// sweepEvery controls how often expired entries are purged, counted in writes
+const sweepEvery = 1000
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Get returns the value for key, or nil if missing or expired
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+
+	return entry.value, nil
+}
+
+// Set stores value for key; a zero exp means no expiration
+func (s *MemoryStore) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := memoryEntry{value: append([]byte(nil), val...)}
+	if exp > 0 {
+		entry.expiresAt = time.Now().Add(exp)
+	}
+	s.entries[key] = entry
+
+	s.sets++
+	if s.sets%sweepEvery == 0 {
+		s.sweep()
+	}
+
+	return nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Reset removes all keys
+func (s *MemoryStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// sweep drops expired entries; callers must hold the lock
+func (s *MemoryStore) sweep() {
+	now := time.Now()
+	for key, entry := range s.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
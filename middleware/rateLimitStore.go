@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"jokes-provider/config"
+	"jokes-provider/utils"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RateLimitStore shares rate limiter counters across replicas through Redis,
+// falling back to process memory while the circuit breaker is open
+type RateLimitStore struct {
+	shared   bool
+	fallback *MemoryStore
+}
+
+// GetRateLimitStore returns a store backed by Redis when caching is enabled
+func GetRateLimitStore() *RateLimitStore {
+	store := &RateLimitStore{fallback: NewMemoryStore()}
+	store.shared = config.CacheConfig.CacheEnabled
+	return store
+}
+
+// IsShared reports whether counters are shared through Redis
+func (s *RateLimitStore) IsShared() bool {
+	return s.shared
+}
+
+// RunScript runs a rate limit script on key, or reports shared as false while
+// counters are kept in memory
+func (s *RateLimitStore) RunScript(script *goredis.Script, key string, args ...any) ([]int64, bool, error) {
+	if !s.shared {
+		return nil, false, nil
+	}
+	store := availableRedis()
+	if store == nil {
+		return nil, false, nil
+	}
+
+	key = utils.CacheKeyPrefixRateLimit + key
+	reply, err := script.Run(context.Background(), store.Conn(), []string{key}, args...).Int64Slice()
+	observeRedis(err)
+	return reply, true, err
+}
+
+// Get returns the in-memory counter state stored for key
+func (s *RateLimitStore) Get(key string) ([]byte, error) {
+	return s.fallback.Get(key)
+}
+
+// Set stores the in-memory counter state for key until exp
+func (s *RateLimitStore) Set(key string, val []byte, exp time.Duration) error {
+	return s.fallback.Set(key, val, exp)
+}
+
+// Delete removes the in-memory counter state for key
+func (s *RateLimitStore) Delete(key string) error {
+	return s.fallback.Delete(key)
+}
+
+// Reset clears the memory counters; resetting Redis would drop the joke cache
+func (s *RateLimitStore) Reset() error {
+	return s.fallback.Reset()
+}
+
+// Close is a no-op, the Redis connection is closed by CloseRedis
+func (s *RateLimitStore) Close() error {
+	return nil
+}
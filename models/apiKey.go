@@ -0,0 +1,9 @@
+package models
+
+// APIKey is a client credential with the routes it may call and its own rate limit
+type APIKey struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	Routes    []string `json:"routes,omitempty"`
+	RateLimit int      `json:"rate_limit,omitempty"`
+}
@@ -5,6 +5,10 @@ type AppConfig struct {
 	Port        string
 	Environment string
 
+	// Graceful shutdown
+	ShutdownGracePeriod string
+	ShutdownTimeout     string
+
 	// Logging configuration
 	LogLevel         string
 	LogFormat        string
@@ -15,15 +19,83 @@ type AppConfig struct {
 	Version string
 	Flavor  string
 
-	// File paths
-	JokesFilePath string
+	// Jokes data source
+	JokesSource      string
+	JokesFilePath    string
+	JokesSQLiteTable string
+
+	// Jokes column mapping
+	JokesColumnID       string
+	JokesColumnJoke     string
+	JokesColumnCategory string
+
+	// Jokes dataset reload
+	JokesReloadEnabled  bool
+	JokesReloadInterval string
+
+	// Jokes API limits
+	RandomMaxCount int
+	BatchMaxIDs    int
+
+	// Random selection
+	RandomMode      string
+	RandomStickyKey string
+
+	// Joke of the day
+	DailyTimezone string
+	DailySeed     string
 
 	// Request headers
 	IPHeaderName      string
+	TrustedProxies    string
 	CountryHeaderName string
+	SessionHeaderName string
+
+	// Sessions
+	SessionCookieName string
+	SessionTTL        string
+
+	// API keys
+	APIKeysEnabled   bool
+	APIKeysFile      string
+	APIKeys          string
+	APIKeyHeaderName string
+
+	// JWT bearer tokens
+	JWTEnabled    bool
+	JWTAlgorithms string
+	JWTSecret     string
+	JWTJWKSFile   string
+	JWTIssuer     string
+	JWTAudience   string
+	JWTLeeway     string
+	JWTScopeClaim string
+
+	// Routes open without an API key or token
+	AnonymousRoutes string
+
+	// Admin API
+	AdminUsername  string
+	AdminPassword  string
+	AdminImportMax int
 
 	// Rate limiter configuration
 	RateLimitEnabled     bool
 	RateLimitMaxRequests int
 	RateLimitDuration    string
+	RateLimitAlgorithm   string
+	RateLimitBurst       int
+	RateLimitPolicyFile  string
+
+	// Prometheus metrics
+	MetricsEnabled bool
+	MetricsPath    string
+	MetricsPort    string
+
+	// OpenTelemetry tracing
+	TracingEnabled     bool
+	TracingExporter    string
+	TracingEndpoint    string
+	TracingSampleRatio string
+	TracingServiceName string
 }
@@ -0,0 +1,8 @@
+package models
+
+// AuthToken is the identity carried by a validated bearer token
+type AuthToken struct {
+	Subject string
+	Issuer  string
+	Scopes  []string
+}
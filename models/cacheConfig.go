@@ -8,4 +8,9 @@ type CacheConfig struct {
 	CacheCaCertPath     string
 	CacheClientCertPath string
 	CacheClientKeyPath  string
+
+	// Circuit breaker around Redis
+	CacheBreakerThreshold     int
+	CacheBreakerProbeInterval string
+	CacheReadiness            string
 }
@@ -1,7 +1,96 @@
 package models
 
-// Joke represents a joke object
+import "time"
+
+// Joke represents a joke object, other source columns kept in Attributes
 type Joke struct {
-	ID   string `json:"id"`
-	Joke string `json:"joke"`
+	ID         string            `json:"id"`
+	Joke       string            `json:"joke"`
+	Category   string            `json:"category,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Category represents a joke category and the number of jokes in it
+type Category struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// JokeList represents one page of the full jokes listing
+type JokeList struct {
+	Data       []Joke `json:"data"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// RandomSelection identifies a random pick so it can be reproduced
+type RandomSelection struct {
+	Seed           string `json:"seed,omitempty"`
+	DatasetVersion string `json:"dataset_version,omitempty"`
+}
+
+// RandomJokeList represents distinct random jokes returned by a single request
+type RandomJokeList struct {
+	Data  []Joke `json:"data"`
+	Count int    `json:"count"`
+	RandomSelection
+}
+
+// DailyJoke represents the joke of a given calendar day
+type DailyJoke struct {
+	Date      string    `json:"date"`
+	Timezone  string    `json:"timezone"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Joke      Joke      `json:"joke"`
+}
+
+// SessionDeck is the state of a session's shuffled deck of jokes
+type SessionDeck struct {
+	Seed     string `json:"seed"`
+	Version  string `json:"version"`
+	Position int    `json:"position"`
+}
+
+// BatchRequest represents a request for several jokes by ID
+type BatchRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchResponse represents the jokes found for a batch request and the IDs that were not found
+type BatchResponse struct {
+	Jokes   []Joke   `json:"jokes"`
+	Missing []string `json:"missing"`
+}
+
+// SearchResult represents a joke matching a search query
+type SearchResult struct {
+	Score float64 `json:"score"`
+	Joke  Joke    `json:"joke"`
+}
+
+// SearchResponse represents one page of search results
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Offset  int            `json:"offset"`
+	Limit   int            `json:"limit"`
+	Results []SearchResult `json:"results"`
+}
+
+// CategoryList represents the list of available categories
+type CategoryList struct {
+	Categories []Category `json:"categories"`
+	Total      int        `json:"total"`
+}
+
+// ImportRequest represents a bulk import of new jokes
+type ImportRequest struct {
+	Jokes []Joke `json:"jokes"`
+}
+
+// ImportResponse lists the imported jokes with their assigned IDs
+type ImportResponse struct {
+	Imported int    `json:"imported"`
+	Jokes    []Joke `json:"jokes"`
 }
@@ -9,6 +9,10 @@ type LogEntry struct {
 	RequestID string `json:"request_id,omitempty"`
 	IPAddress string `json:"ip_address,omitempty"`
 	Country   string `json:"country,omitempty"`
+	APIKey    string `json:"api_key,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	SpanID    string `json:"span_id,omitempty"`
 	Message   string `json:"message"`
 }
 
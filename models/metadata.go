@@ -7,8 +7,13 @@ type Metadata struct {
 	Logging     LoggingInfo     `json:"logging"`
 	Cache       CacheInfo       `json:"cache"`
 	Files       FilesInfo       `json:"files"`
+	Dataset     DatasetInfo     `json:"dataset"`
+	Random      RandomInfo      `json:"random"`
 	Headers     HeadersInfo     `json:"headers"`
 	RateLimiter RateLimiterInfo `json:"rate_limiter"`
+	Auth        AuthInfo        `json:"auth"`
+	Metrics     MetricsInfo     `json:"metrics"`
+	Tracing     TracingInfo     `json:"tracing"`
 	Fiber       FiberInfo       `json:"fiber"`
 }
 
@@ -32,24 +37,93 @@ type LoggingInfo struct {
 }
 
 type CacheInfo struct {
-	Enabled bool   `json:"enabled"`
-	URL     string `json:"url"`
-	TTL     string `json:"ttl"`
+	Enabled   bool             `json:"enabled"`
+	URL       string           `json:"url"`
+	TTL       string           `json:"ttl"`
+	Readiness string           `json:"readiness"`
+	Breaker   CacheBreakerInfo `json:"breaker"`
+}
+
+// CacheBreakerInfo describes the circuit breaker around Redis
+type CacheBreakerInfo struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Threshold           int    `json:"threshold"`
+	ProbeInterval       string `json:"probe_interval"`
+	OpenedAt            string `json:"opened_at,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
 }
 
 type FilesInfo struct {
 	JokesPath string `json:"jokes_path"`
 }
 
+type DatasetInfo struct {
+	JokeCount      int    `json:"joke_count"`
+	Source         string `json:"source"`
+	Version        string `json:"version,omitempty"`
+	LoadedAt       string `json:"loaded_at,omitempty"`
+	ReloadEnabled  bool   `json:"reload_enabled"`
+	ReloadInterval string `json:"reload_interval"`
+	ReloadCount    int64  `json:"reload_count"`
+	FailureCount   int64  `json:"failure_count"`
+	LastReloadAt   string `json:"last_reload_at,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	LastErrorAt    string `json:"last_error_at,omitempty"`
+}
+
+type RandomInfo struct {
+	Mode        string `json:"mode"`
+	StickyKey   string `json:"sticky_key,omitempty"`
+	StickyTTL   string `json:"sticky_ttl,omitempty"`
+	Description string `json:"description"`
+}
+
 type HeadersInfo struct {
-	IPHeaderName      string `json:"ip_header_name"`
-	CountryHeaderName string `json:"country_header_name"`
+	IPHeaderName      string   `json:"ip_header_name"`
+	TrustedProxies    []string `json:"trusted_proxies"`
+	CountryHeaderName string   `json:"country_header_name"`
+	SessionHeaderName string   `json:"session_header_name"`
 }
 
 type RateLimiterInfo struct {
+	Enabled          bool              `json:"enabled"`
+	MaxRequests      int               `json:"max_requests"`
+	Duration         string            `json:"duration"`
+	Algorithm        string            `json:"algorithm"`
+	Burst            int               `json:"burst,omitempty"`
+	Storage          string            `json:"storage,omitempty"`
+	PolicyFile       string            `json:"policy_file,omitempty"`
+	ExemptRoutes     []string          `json:"exempt_routes,omitempty"`
+	ExemptNetworks   []string          `json:"exempt_networks,omitempty"`
+	InternalNetworks []string          `json:"internal_networks,omitempty"`
+	Policies         []RateLimitPolicy `json:"policies,omitempty"`
+}
+
+type AuthInfo struct {
+	APIKeysEnabled   bool     `json:"api_keys_enabled"`
+	APIKeyCount      int      `json:"api_key_count"`
+	APIKeyHeaderName string   `json:"api_key_header_name"`
+	JWTEnabled       bool     `json:"jwt_enabled"`
+	JWTAlgorithms    []string `json:"jwt_algorithms,omitempty"`
+	JWTIssuer        string   `json:"jwt_issuer,omitempty"`
+	JWTAudience      string   `json:"jwt_audience,omitempty"`
+	AnonymousRoutes  []string `json:"anonymous_routes,omitempty"`
+	AdminEnabled     bool     `json:"admin_enabled"`
+}
+
+type MetricsInfo struct {
+	Enabled bool   `json:"enabled"`
+	Path    string `json:"path,omitempty"`
+	Port    string `json:"port,omitempty"`
+}
+
+type TracingInfo struct {
 	Enabled     bool   `json:"enabled"`
-	MaxRequests int    `json:"max_requests"`
-	Duration    string `json:"duration"`
+	Exporter    string `json:"exporter,omitempty"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	SampleRatio string `json:"sample_ratio,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
 }
 
 type FiberInfo struct {
@@ -0,0 +1,11 @@
+package models
+
+// Problem represents an RFC 7807 problem details error response
+type Problem struct {
+	Type      string `json:"type" example:"/problems/not-found"`
+	Title     string `json:"title" example:"Not Found"`
+	Status    int    `json:"status" example:"404"`
+	Detail    string `json:"detail,omitempty" example:"Joke not found"`
+	Instance  string `json:"instance,omitempty" example:"/v1/jokes/42"`
+	RequestID string `json:"request_id,omitempty" example:"3f6c2a8e-5d1b-4e7a-9c0f-2b8d7e6a1c4f"`
+}
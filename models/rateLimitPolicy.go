@@ -0,0 +1,21 @@
+package models
+
+// RateLimitPolicy limits the requests of one client tier on a set of routes
+type RateLimitPolicy struct {
+	Name        string   `json:"name"`
+	Routes      []string `json:"routes,omitempty"`
+	Tiers       []string `json:"tiers,omitempty"`
+	MaxRequests int      `json:"max_requests,omitempty"`
+	Window      string   `json:"window,omitempty"`
+	Algorithm   string   `json:"algorithm,omitempty"`
+	Burst       int      `json:"burst,omitempty"`
+	Exempt      bool     `json:"exempt,omitempty"`
+}
+
+// RateLimitPolicySet is the content of the rate limit policy file
+type RateLimitPolicySet struct {
+	ExemptRoutes     []string          `json:"exempt_routes"`
+	ExemptNetworks   []string          `json:"exempt_networks,omitempty"`
+	InternalNetworks []string          `json:"internal_networks,omitempty"`
+	Policies         []RateLimitPolicy `json:"policies"`
+}
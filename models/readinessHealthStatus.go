@@ -2,7 +2,9 @@ package models
 
 // HealthStatus represents the health check result
 type ReadinessHealthStatus struct {
-	Ready  bool   `json:"ready"`
+	Ready bool `json:"ready"`
+	// Status is ready, degraded (serving without Redis) or not_ready
+	Status string `json:"status"`
 	Reason string `json:"reason,omitempty"`
 	Redis  string `json:"redis,omitempty"`
 	CSV    string `json:"csv,omitempty"`
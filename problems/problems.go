@@ -0,0 +1,163 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"jokes-provider/config"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error is an error answered with an RFC 7807 problem details response by Handler
+type Error struct {
+	// Type is one of the utils.ProblemType* values, or empty for about:blank
+	Type   string
+	Status int
+	Detail string
+	// Extensions are added to the response after the standard members
+	Extensions map[string]any
+	// Err is the underlying cause. It is logged but never sent.
+	Err error
+}
+
+func (e *Error) Error() string {
+	message := http.StatusText(e.Status)
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// With adds an extension member to the response and returns the error
+func (e *Error) With(key string, value any) *Error {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// NotFound reports a missing resource
+func NotFound(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeNotFound, Status: fiber.StatusNotFound, Detail: detail}
+}
+
+// Validation reports an invalid request parameter or body
+func Validation(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeValidation, Status: fiber.StatusBadRequest, Detail: detail}
+}
+
+// Unauthorized reports missing or invalid credentials
+func Unauthorized(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeUnauthorized, Status: fiber.StatusUnauthorized, Detail: detail}
+}
+
+// Forbidden reports credentials that do not allow the request
+func Forbidden(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeForbidden, Status: fiber.StatusForbidden, Detail: detail}
+}
+
+// Conflict reports a request that conflicts with the current data
+func Conflict(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeConflict, Status: fiber.StatusConflict, Detail: detail}
+}
+
+// NotAcceptable reports that no acceptable response format is supported
+func NotAcceptable(detail string) *Error {
+	return &Error{Type: utils.ProblemTypeNotAcceptable, Status: fiber.StatusNotAcceptable, Detail: detail}
+}
+
+// RateLimited reports a request rejected by the rate limiter
+func RateLimited(retryAfter int) *Error {
+	return (&Error{Type: utils.ProblemTypeRateLimited, Status: fiber.StatusTooManyRequests, Detail: utils.ErrMsgTooManyRequests}).
+		With(utils.JSONKeyRetryAfter, retryAfter)
+}
+
+// UpstreamUnavailable reports that a backing store the request needs cannot be used
+func UpstreamUnavailable(detail string, err error) *Error {
+	return &Error{Type: utils.ProblemTypeUpstreamUnavailable, Status: fiber.StatusServiceUnavailable, Detail: detail, Err: err}
+}
+
+// Internal reports an unexpected failure. Its cause is only logged.
+func Internal(detail string, err error) *Error {
+	return &Error{Type: utils.ProblemTypeInternal, Status: fiber.StatusInternalServerError, Detail: detail, Err: err}
+}
+
+// StatusCode returns the status a handler error is answered with
+func StatusCode(err error) int {
+	var problem *Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &problem):
+		return problem.Status
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Handler renders every error as a problem details response; errors other
+// than problems and Fiber's own are internal errors
+func Handler(c *fiber.Ctx, err error) error {
+	var problem *Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &problem):
+	case errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound:
+		problem = NotFound(utils.ErrMsgRouteNotFound)
+	case errors.As(err, &fiberErr):
+		problem = &Error{Status: fiberErr.Code, Detail: fiberErr.Message}
+	default:
+		problem = Internal(utils.ErrMsgInternal, err)
+	}
+
+	if problem.Status >= fiber.StatusInternalServerError {
+		config.LogError(c, "Request failed", "status", problem.Status, utils.JSONKeyError, err.Error())
+	}
+
+	problemType := utils.ProblemTypeBlank
+	if problem.Type != "" {
+		problemType = utils.ProblemTypeBase + problem.Type
+	}
+
+	body, err := encode(models.Problem{
+		Type:      problemType,
+		Title:     http.StatusText(problem.Status),
+		Status:    problem.Status,
+		Detail:    problem.Detail,
+		Instance:  c.Path(),
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+	}, problem.Extensions)
+	if err != nil {
+		return err
+	}
+
+	c.Set(utils.HeaderContentType, utils.MIMEApplicationProblemJSON)
+	return c.Status(problem.Status).Send(body)
+}
+
+// encode marshals the standard members followed by the extension members
+func encode(problem models.Problem, extensions map[string]any) ([]byte, error) {
+	body, err := json.Marshal(problem)
+	if err != nil || len(extensions) == 0 {
+		return body, err
+	}
+
+	extra, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Join the two objects: drop the closing brace of one and the opening brace of the other
+	return append(append(body[:len(body)-1], ','), extra[1:]...), nil
+}
@@ -14,19 +14,36 @@ func RegisterRoutes(app *fiber.App) {
 	jokeCtrl := controllers.NewJokeController()
 	healthCtrl := controllers.NewHealthController()
 	metadataCtrl := controllers.NewMetadataController()
+	adminCtrl := controllers.NewAdminController()
 
 	// API v1 group
 	v1 := app.Group(utils.APIVersionV1)
 	{
 		// Jokes group
-		jokes := v1.Group(utils.RouteJokes)
+		jokes := v1.Group(utils.RouteJokes, services.RequireScope(utils.ScopeJokesRead), services.NegotiateFormat())
 		{
+			jokes.Get(utils.ListJokesEndpoint, jokeCtrl.ListJokes)
 			jokes.Get(utils.RandomJokeEndpoint, jokeCtrl.GetRandomJoke)
+			jokes.Get(utils.DailyEndpoint, jokeCtrl.GetDailyJoke)
+			jokes.Get(utils.CategoriesEndpoint, jokeCtrl.GetCategories)
+			jokes.Get(utils.SearchEndpoint, jokeCtrl.SearchJokes)
+			jokes.Post(utils.BatchEndpoint, jokeCtrl.GetJokesBatch)
 			jokes.Get(utils.JokeByIDEndpoint, jokeCtrl.GetJokeByID)
 		}
 
 		// Metadata group
-		v1.Get(utils.MetadataEndpoint, metadataCtrl.GetMetadata)
+		v1.Get(utils.MetadataEndpoint, services.RequireScope(utils.ScopeMetadataRead), services.NegotiateFormat(), metadataCtrl.GetMetadata)
+	}
+
+	// Admin group, only registered when admin credentials, API keys or JWTs are configured
+	if adminAuth := services.SetupAdminAuth(); adminAuth != nil {
+		adminJokes := app.Group(utils.RouteAdmin+utils.APIVersionV1+utils.RouteJokes, adminAuth, services.RequireScope(utils.ScopeJokesWrite))
+		{
+			adminJokes.Post(utils.ListJokesEndpoint, adminCtrl.CreateJoke)
+			adminJokes.Post(utils.ImportEndpoint, adminCtrl.ImportJokes)
+			adminJokes.Put(utils.JokeByIDEndpoint, adminCtrl.UpdateJoke)
+			adminJokes.Delete(utils.JokeByIDEndpoint, adminCtrl.DeleteJoke)
+		}
 	}
 
 	// Health group (outside API versioning)
@@ -36,6 +53,9 @@ func RegisterRoutes(app *fiber.App) {
 		health.Use(healthCtrl.SetupLivenessProbe(utils.LivenessEndpoint))
 	}
 
+	// Prometheus metrics, unless served on their own port
+	services.SetupMetrics(app)
+
 	// Swagger
 	services.SetupSwagger(app)
 }
@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"io"
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/problems"
+	"jokes-provider/services"
+	"jokes-provider/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestMain runs from the repository root, where the swagger spec is read from
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	config.LoadEnvVars()
+	config.InitializeLogger(fiber.New())
+	os.Exit(m.Run())
+}
+
+// newAdminTestApp mounts the routes behind the global auth middleware, the way
+// the service does, with admin credentials admin:pw and a writable CSV source
+func newAdminTestApp(t *testing.T, configure func()) *fiber.App {
+	t.Helper()
+
+	config.LoadEnvVars()
+	config.AppConfig.AdminUsername = "admin"
+	config.AppConfig.AdminPassword = "pw"
+	config.AppConfig.MetricsEnabled = false
+	configure()
+
+	path := filepath.Join(t.TempDir(), "jokes.csv")
+	if err := os.WriteFile(path, []byte("id,joke\n1,first\n"), 0o600); err != nil {
+		t.Fatalf("writing jokes file: %v", err)
+	}
+	source, err := helpers.NewJokeSource(utils.JokesSourceCSV, path, "")
+	if err != nil {
+		t.Fatalf("NewJokeSource error: %v", err)
+	}
+	if err := helpers.LoadJokes(nil, source); err != nil {
+		t.Fatalf("LoadJokes error: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: problems.Handler})
+	app.Use(services.SetupAuth())
+	RegisterRoutes(app)
+	return app
+}
+
+type authTestCase struct {
+	name          string
+	method        string
+	path          string
+	authorization string
+	apiKey        string
+	want          int
+}
+
+func runAuthTests(t *testing.T, app *fiber.App, tests []authTestCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader(`{"joke":"new one"}`)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(config.AppConfig.APIKeyHeaderName, tt.apiKey)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				data, _ := io.ReadAll(resp.Body)
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, resp.StatusCode, tt.want, data)
+			}
+		})
+	}
+}
+
+const (
+	adminBasicAuth = "Basic YWRtaW46cHc="     // admin:pw
+	wrongBasicAuth = "Basic YWRtaW46d3Jvbmc=" // admin:wrong
+)
+
+func TestAdminBasicAuthWithAPIKeys(t *testing.T) {
+	app := newAdminTestApp(t, func() {
+		config.AppConfig.APIKeysEnabled = true
+		config.AppConfig.APIKeys = "reader:r1"
+		if err := helpers.LoadAPIKeys("", config.AppConfig.APIKeys); err != nil {
+			t.Fatalf("LoadAPIKeys error: %v", err)
+		}
+	})
+
+	runAuthTests(t, app, []authTestCase{
+		{name: "basic auth", method: http.MethodPost, path: "/admin/v1/jokes", authorization: adminBasicAuth, want: fiber.StatusCreated},
+		{name: "wrong password", method: http.MethodPost, path: "/admin/v1/jokes", authorization: wrongBasicAuth, want: fiber.StatusUnauthorized},
+		{name: "no credentials", method: http.MethodPost, path: "/admin/v1/jokes", want: fiber.StatusUnauthorized},
+		{name: "key without admin routes", method: http.MethodPost, path: "/admin/v1/jokes", apiKey: "r1", want: fiber.StatusForbidden},
+		{name: "unknown key", method: http.MethodPost, path: "/admin/v1/jokes", apiKey: "nope", want: fiber.StatusUnauthorized},
+		{name: "public route still needs a key", method: http.MethodGet, path: "/v1/jokes", authorization: adminBasicAuth, want: fiber.StatusUnauthorized},
+		{name: "public route with key", method: http.MethodGet, path: "/v1/jokes", apiKey: "r1", want: fiber.StatusOK},
+	})
+}
+
+func TestAdminBasicAuthWithJWT(t *testing.T) {
+	const secret = "test-secret-of-at-least-32-bytes!"
+	app := newAdminTestApp(t, func() {
+		config.AppConfig.JWTEnabled = true
+		config.AppConfig.JWTSecret = secret
+		err := helpers.ConfigureJWT(helpers.JWTSettings{Algorithms: []string{"HS256"}, Secret: secret, ScopeClaim: "scope"})
+		if err != nil {
+			t.Fatalf("ConfigureJWT error: %v", err)
+		}
+	})
+
+	token := func(scope string) string {
+		claims := jwt.MapClaims{"sub": "partner", "scope": scope, "exp": time.Now().Add(time.Hour).Unix()}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("signing token: %v", err)
+		}
+		return "Bearer " + signed
+	}
+
+	runAuthTests(t, app, []authTestCase{
+		{name: "basic auth", method: http.MethodPost, path: "/admin/v1/jokes", authorization: adminBasicAuth, want: fiber.StatusCreated},
+		{name: "wrong password", method: http.MethodPost, path: "/admin/v1/jokes", authorization: wrongBasicAuth, want: fiber.StatusUnauthorized},
+		{name: "no credentials", method: http.MethodPost, path: "/admin/v1/jokes", want: fiber.StatusUnauthorized},
+		{name: "token with write scope", method: http.MethodPost, path: "/admin/v1/jokes", authorization: token(utils.ScopeJokesWrite), want: fiber.StatusCreated},
+		{name: "token without write scope", method: http.MethodPost, path: "/admin/v1/jokes", authorization: token(utils.ScopeJokesRead), want: fiber.StatusForbidden},
+		{name: "invalid token", method: http.MethodPost, path: "/admin/v1/jokes", authorization: "Bearer a.b.c", want: fiber.StatusUnauthorized},
+		{name: "public route still needs a token", method: http.MethodGet, path: "/v1/jokes", authorization: adminBasicAuth, want: fiber.StatusUnauthorized},
+		{name: "public route with token", method: http.MethodGet, path: "/v1/jokes", authorization: token(utils.ScopeJokesRead), want: fiber.StatusOK},
+	})
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+	"jokes-provider/wrapper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminService struct{}
+
+func NewAdminService() *AdminService {
+	return &AdminService{}
+}
+
+// CreateJokes adds the jokes to the data source, assigning IDs where missing
+func (s *AdminService) CreateJokes(c *fiber.Ctx, jokes []models.Joke) ([]models.Joke, error) {
+	created, err := helpers.CreateJokes(c, jokes)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(created))
+	for i, joke := range created {
+		ids[i] = joke.ID
+	}
+	invalidateJokes(c, ids...)
+
+	config.LogInfo(c, "Jokes created", "count", len(created))
+	return created, nil
+}
+
+func (s *AdminService) UpdateJoke(c *fiber.Ctx, jokeID string, joke models.Joke) (models.Joke, error) {
+	updated, err := helpers.UpdateJoke(c, jokeID, joke)
+	if err != nil {
+		return models.Joke{}, err
+	}
+
+	invalidateJokes(c, jokeID)
+
+	config.LogInfo(c, "Joke updated", "id", jokeID)
+	return updated, nil
+}
+
+func (s *AdminService) DeleteJoke(c *fiber.Ctx, jokeID string) error {
+	if err := helpers.DeleteJoke(c, jokeID); err != nil {
+		return err
+	}
+
+	invalidateJokes(c, jokeID)
+
+	config.LogInfo(c, "Joke deleted", "id", jokeID)
+	return nil
+}
+
+// invalidateJokes drops the joke:<id> entries cached by GetJokeByID
+func invalidateJokes(c *fiber.Ctx, jokeIDs ...string) {
+	cacheKeys := make([]string, len(jokeIDs))
+	for i, jokeID := range jokeIDs {
+		cacheKeys[i] = utils.CacheKeyPrefixJoke + jokeID
+	}
+	_ = wrapper.InvalidateCache(c, cacheKeys...)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/basicauth"
+)
+
+// adminRealm is announced to clients in the WWW-Authenticate header
+const adminRealm = "Jokes Provider Admin"
+
+// hasAdminGrant reports whether the request carries a JWT or an API key listing the admin routes
+func hasAdminGrant(c *fiber.Ctx) bool {
+	if HasToken(c) {
+		return true
+	}
+	key := ResolveAPIKey(c)
+	return key != nil && len(key.Routes) > 0 && helpers.MatchRoute(key.Routes, c.Path())
+}
+
+// rejectUngrantedKey refuses API keys that are valid but not granted the admin routes
+func rejectUngrantedKey(c *fiber.Ctx) error {
+	if HasAPIKey(c) && !hasAdminGrant(c) {
+		config.LogInfo(c, "API key not granted the admin routes", "path", c.Path())
+		return problems.Forbidden(utils.ErrMsgAdminForbidden)
+	}
+	return nil
+}
+
+// SetupAdminAuth returns the middleware guarding the admin API with basic auth,
+// API keys or JWTs, or nil when the admin API is disabled
+func SetupAdminAuth() fiber.Handler {
+	if config.AppConfig.AdminUsername == "" || config.AppConfig.AdminPassword == "" {
+		if !config.AppConfig.APIKeysEnabled && !config.AppConfig.JWTEnabled {
+			config.LogInfo(nil, "Admin API is disabled")
+			return nil
+		}
+
+		config.LogInfo(nil, "Admin API enabled for API keys and tokens only")
+		return func(c *fiber.Ctx) error {
+			if err := rejectUngrantedKey(c); err != nil {
+				return err
+			}
+			if hasAdminGrant(c) {
+				return c.Next()
+			}
+			return problems.Unauthorized(utils.ErrMsgAuthRequired)
+		}
+	}
+
+	config.LogInfo(nil, "Admin API enabled", "username", config.AppConfig.AdminUsername)
+
+	basicAuth := basicauth.New(basicauth.Config{
+		// Token scopes are checked next
+		Next: hasAdminGrant,
+		Users: map[string]string{
+			config.AppConfig.AdminUsername: config.AppConfig.AdminPassword,
+		},
+		Realm: adminRealm,
+		Unauthorized: func(c *fiber.Ctx) error {
+			config.LogInfo(c, "Admin authentication failed")
+			c.Set(utils.HeaderWWWAuth, `Basic realm="`+adminRealm+`"`)
+			return problems.Unauthorized(utils.ErrMsgUnauthorized)
+		},
+	})
+
+	return func(c *fiber.Ctx) error {
+		if err := rejectUngrantedKey(c); err != nil {
+			return err
+		}
+		return basicAuth(c)
+	}
+}
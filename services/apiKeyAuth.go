@@ -0,0 +1,55 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/models"
+	"jokes-provider/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiKeyLookup caches the outcome of resolving the request's API key
+type apiKeyLookup struct {
+	key       *models.APIKey
+	presented bool
+}
+
+// ResolveAPIKey returns the API key sent in its header or as a bearer token, or nil
+func ResolveAPIKey(c *fiber.Ctx) *models.APIKey {
+	return lookupAPIKey(c).key
+}
+
+// HasAPIKey reports whether the request is authenticated by a valid API key
+func HasAPIKey(c *fiber.Ctx) bool {
+	return ResolveAPIKey(c) != nil
+}
+
+func lookupAPIKey(c *fiber.Ctx) apiKeyLookup {
+	if !config.AppConfig.APIKeysEnabled {
+		return apiKeyLookup{}
+	}
+
+	if lookup, ok := c.Locals(utils.LocalsAPIKey).(apiKeyLookup); ok {
+		return lookup
+	}
+
+	secret := c.Get(config.AppConfig.APIKeyHeaderName)
+	if secret == "" {
+		// Signed tokens are left to the JWT check
+		if token := bearerToken(c); !config.AppConfig.JWTEnabled || !helpers.LooksLikeJWT(token) {
+			secret = token
+		}
+	}
+
+	lookup := apiKeyLookup{presented: secret != ""}
+	if lookup.presented {
+		lookup.key, _ = helpers.FindAPIKey(secret)
+	}
+	c.Locals(utils.LocalsAPIKey, lookup)
+	if lookup.key != nil {
+		c.Locals(utils.LocalsAPIKeyName, lookup.key.Name)
+	}
+
+	return lookup
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(c *fiber.Ctx) string {
+	authorization := c.Get(utils.HeaderAuthorize)
+	if len(authorization) <= len(utils.AuthSchemeBearer) ||
+		!strings.EqualFold(authorization[:len(utils.AuthSchemeBearer)], utils.AuthSchemeBearer) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(utils.AuthSchemeBearer):])
+}
+
+// SetupAuth returns the middleware requiring a valid API key or JWT outside
+// AUTH_ANONYMOUS_ROUTES; the admin API is left to SetupAdminAuth
+func SetupAuth() fiber.Handler {
+	if !config.AppConfig.APIKeysEnabled && !config.AppConfig.JWTEnabled {
+		config.LogInfo(nil, "API key and JWT authentication are disabled")
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	anonymousRoutes := helpers.SplitList(config.AppConfig.AnonymousRoutes)
+
+	config.LogInfo(nil, "Authentication initialized",
+		"api_keys", config.AppConfig.APIKeysEnabled, "api_key_count", len(helpers.GetAPIKeys()),
+		"jwt", config.AppConfig.JWTEnabled, "anonymous_routes", strings.Join(anonymousRoutes, ","))
+
+	adminRoutes := []string{utils.RouteAdmin + "/*"}
+
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if helpers.MatchRoute(adminRoutes, path) {
+			return c.Next()
+		}
+
+		// Wrong credentials are rejected even on anonymous routes, so mistakes surface early
+		if token := lookupToken(c); token.presented {
+			if token.err != nil {
+				config.LogInfo(c, "Invalid token", utils.JSONKeyError, token.err.Error())
+				c.Set(utils.HeaderWWWAuth, `Bearer error="invalid_token"`)
+				return problems.Unauthorized(utils.ErrMsgInvalidToken)
+			}
+			return c.Next()
+		}
+
+		lookup := lookupAPIKey(c)
+		if lookup.presented && lookup.key == nil {
+			config.LogInfo(c, "Invalid API key")
+			return problems.Unauthorized(utils.ErrMsgInvalidAPIKey)
+		}
+
+		if lookup.key != nil {
+			if len(lookup.key.Routes) > 0 && !helpers.MatchRoute(lookup.key.Routes, path) {
+				config.LogInfo(c, "API key not allowed for route", "path", path)
+				return problems.Forbidden(utils.ErrMsgRouteForbidden)
+			}
+			return c.Next()
+		}
+
+		if helpers.MatchRoute(anonymousRoutes, path) {
+			return c.Next()
+		}
+
+		if !config.AppConfig.JWTEnabled {
+			return problems.Unauthorized(utils.ErrMsgAPIKeyRequired)
+		}
+
+		c.Set(utils.HeaderWWWAuth, "Bearer")
+		return problems.Unauthorized(utils.ErrMsgAuthRequired)
+	}
+}
@@ -3,7 +3,10 @@ package services
 import (
 	"jokes-provider/config"
 	"jokes-provider/helpers"
+	"jokes-provider/middleware"
 	"jokes-provider/models"
+	"jokes-provider/utils"
+	"sync/atomic"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
@@ -12,6 +15,14 @@ import (
 // HealthService handles health check business logic
 type HealthService struct{}
 
+// shuttingDown fails the readiness probe once shutdown begins
+var shuttingDown atomic.Bool
+
+// MarkShuttingDown makes the readiness probe fail from now on
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
 // NewHealthService creates a new HealthService instance
 func NewHealthService() *HealthService {
 	return &HealthService{}
@@ -19,29 +30,69 @@ func NewHealthService() *HealthService {
 
 // CheckReadiness checks if the service is ready
 func (s *HealthService) CheckReadiness(c *fiber.Ctx) models.ReadinessHealthStatus {
-	// Check Redis status
-	if !helpers.CheckRedisStatus(c) {
-		config.LogError(c, "Readiness check failed: Redis unavailable")
+	if shuttingDown.Load() {
+		config.LogInfo(c, "Readiness check failed: shutting down")
+		return models.ReadinessHealthStatus{
+			Ready:  false,
+			Status: utils.ReadinessNotReady,
+			Reason: "Shutting down",
+		}
+	}
+
+	// Check Redis status; jokes are served from memory, so an outage only degrades the service
+	cacheEnabled := config.CacheConfig.CacheEnabled
+	redisUp := !cacheEnabled || helpers.CheckRedisStatus(c)
+	if !redisUp && config.CacheConfig.CacheReadiness != utils.CacheReadinessOptional {
+		config.LogError(c, "Readiness check failed: Redis unavailable", "breaker", middleware.GetCacheBreakerInfo().State)
 		return models.ReadinessHealthStatus{
 			Ready:  false,
+			Status: utils.ReadinessNotReady,
 			Reason: "Redis unavailable",
 		}
 	}
 
-	// Check if CSV file is accessible
+	// Check if the jokes file or directory is accessible
 	if !config.FileExists(config.AppConfig.JokesFilePath) {
-		config.LogError(c, "Readiness check failed: Jokes CSV file not accessible", "path", config.AppConfig.JokesFilePath)
+		config.LogError(c, "Readiness check failed: Jokes data source not accessible", "path", config.AppConfig.JokesFilePath)
 		return models.ReadinessHealthStatus{
 			Ready:  false,
-			Reason: "Jokes CSV file not accessible",
+			Status: utils.ReadinessNotReady,
+			Reason: "Jokes data source not accessible",
 		}
 	}
 
+	// Check if jokes are loaded in memory
+	if helpers.GetJokeRepository().Count() == 0 {
+		config.LogError(c, "Readiness check failed: Jokes dataset not loaded")
+		return models.ReadinessHealthStatus{
+			Ready:  false,
+			Status: utils.ReadinessNotReady,
+			Reason: "Jokes dataset not loaded",
+		}
+	}
+
+	if !redisUp {
+		config.LogInfo(c, "Readiness check passed in degraded mode: Redis unavailable", "breaker", middleware.GetCacheBreakerInfo().State)
+		return models.ReadinessHealthStatus{
+			Ready:  true,
+			Status: utils.ReadinessDegraded,
+			Reason: "Redis unavailable, serving without cache",
+			Redis:  "unavailable",
+			CSV:    "accessible",
+		}
+	}
+
+	redis := "connected"
+	if !cacheEnabled {
+		redis = "disabled"
+	}
+
 	config.LogInfo(c, "Readiness check passed")
 	return models.ReadinessHealthStatus{
-		Ready: true,
-		Redis: "connected",
-		CSV:   "accessible",
+		Ready:  true,
+		Status: utils.ReadinessReady,
+		Redis:  redis,
+		CSV:    "accessible",
 	}
 }
 
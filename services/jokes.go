@@ -1,50 +1,234 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"jokes-provider/config"
 	"jokes-provider/helpers"
+	"jokes-provider/models"
 	"jokes-provider/utils"
 	"jokes-provider/wrapper"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-type JokeService struct{}
+type JokeService struct {
+	repository helpers.JokeRepository
+	random     helpers.RandomSource
+}
 
 func NewJokeService() *JokeService {
-	return &JokeService{}
+	return NewJokeServiceWithRandom(helpers.GetJokeRepository(), helpers.NewGlobalRandomSource())
+}
+
+// NewJokeServiceWithRandom creates a JokeService with an explicit repository and seed generator
+func NewJokeServiceWithRandom(repository helpers.JokeRepository, random helpers.RandomSource) *JokeService {
+	return &JokeService{
+		repository: repository,
+		random:     random,
+	}
+}
+
+// GetRandomJoke picks a random joke derived from the seed, generated when empty,
+// so it can be replayed
+func (s *JokeService) GetRandomJoke(c *fiber.Ctx, seed string, categories []string) (models.Joke, models.RandomSelection, error) {
+	if seed == "" {
+		seed = s.resolveSeed(c, categories)
+	}
+
+	joke, version, err := s.repository.GetRandom(seed, categories)
+	if err != nil {
+		return models.Joke{}, models.RandomSelection{}, err
+	}
+
+	return joke, models.RandomSelection{Seed: seed, DatasetVersion: version}, nil
 }
 
-func (s *JokeService) GetRandomJoke(c *fiber.Ctx, cacheKey string) (map[string]string, error) {
+// resolveSeed returns the caller's cached seed in sticky mode, or a new one
+func (s *JokeService) resolveSeed(c *fiber.Ctx, categories []string) string {
+	cacheKey := stickyCacheKey(c, categories)
+	if cacheKey == "" {
+		return helpers.NewRandomSeed(s.random)
+	}
+
 	// Try cache first
-	if cached, ok := wrapper.ReadCacheIfAllowed(c, cacheKey); ok {
-		return cached, nil
+	if cached, ok := wrapper.ReadCacheIfAllowed[map[string]string](c, cacheKey); ok && cached[utils.JSONKeySeed] != "" {
+		return cached[utils.JSONKeySeed]
+	}
+
+	// Cache miss - generate and remember a seed for this caller
+	seed := helpers.NewRandomSeed(s.random)
+	_ = wrapper.WriteCacheIfAllowed(c, cacheKey, map[string]string{utils.JSONKeySeed: seed})
+
+	return seed
+}
+
+// IsStickyRandom reports whether random jokes are cached per client or session
+func IsStickyRandom() bool {
+	return config.AppConfig.RandomMode == utils.RandomModeSticky
+}
+
+// stickyCacheKey returns the cache key of the caller's sticky seed, empty when not sticky
+func stickyCacheKey(c *fiber.Ctx, categories []string) string {
+	if !IsStickyRandom() {
+		return ""
+	}
+
+	var identity string
+	if config.AppConfig.RandomStickyKey == utils.RandomStickyKeySession {
+		identity = c.Get(config.AppConfig.SessionHeaderName)
+	} else {
+		identity = config.ClientIP(c)
+	}
+
+	if identity == "" {
+		return ""
+	}
+
+	// Hash the identity so client IPs and session IDs never appear in Redis keys
+	hash := sha256.Sum256([]byte(identity))
+	cacheKey := utils.CacheKeyPrefixSticky + config.AppConfig.RandomStickyKey + ":" + hex.EncodeToString(hash[:8])
+
+	if len(categories) > 0 {
+		cacheKey += ":" + utils.QueryCategory + ":" + helpers.CategoryKey(categories)
+	}
+
+	return cacheKey
+}
+
+// GetRandomJokes picks count distinct random jokes derived from the seed, like GetRandomJoke
+func (s *JokeService) GetRandomJokes(c *fiber.Ctx, seed string, categories []string, count int) (models.RandomJokeList, error) {
+	if seed == "" {
+		seed = s.resolveSeed(c, categories)
 	}
 
-	// Cache miss - get from data source
-	joke, err := helpers.GetRandomJoke(c)
+	jokes, version, err := s.repository.GetRandomSample(seed, categories, count)
 	if err != nil {
-		return nil, err
+		return models.RandomJokeList{}, err
 	}
 
-	// Write to cache
-	_ = wrapper.WriteCacheIfAllowed(c, cacheKey, joke)
+	return models.RandomJokeList{
+		Data:  jokes,
+		Count: len(jokes),
+		RandomSelection: models.RandomSelection{
+			Seed:           seed,
+			DatasetVersion: version,
+		},
+	}, nil
+}
 
-	return joke, nil
+// GetSessionJokes deals count unseen jokes from the session's deck and returns how many remain
+func (s *JokeService) GetSessionJokes(c *fiber.Ctx, sessionID string, categories []string, count int) ([]models.Joke, int, error) {
+	key := helpers.SessionDeckKey(sessionID, categories)
+	deck := helpers.LoadSessionDeck(c, key)
+
+	jokes, remaining, err := s.repository.DrawFromDeck(&deck, categories, count, func() string {
+		return helpers.NewRandomSeed(s.random)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_ = helpers.SaveSessionDeck(c, key, deck)
+
+	return jokes, remaining, nil
+}
+
+func (s *JokeService) GetDailyJoke(c *fiber.Ctx, timezone, date string) (models.DailyJoke, error) {
+	now := time.Now()
+
+	day, err := helpers.ResolveDailyDate(timezone, date, now)
+	if err != nil {
+		return models.DailyJoke{}, err
+	}
+
+	joke, err := s.repository.GetDeterministic(helpers.DailyKey(config.AppConfig.DailySeed, day))
+	if err != nil {
+		return models.DailyJoke{}, err
+	}
+
+	return models.DailyJoke{
+		Date:      day.Format(helpers.DailyDateLayout),
+		Timezone:  day.Location().String(),
+		ExpiresAt: helpers.NextDayBoundary(day, now),
+		Joke:      joke,
+	}, nil
 }
 
-func (s *JokeService) GetJokeByID(c *fiber.Ctx, jokeID string) (map[string]string, error) {
+func (s *JokeService) GetJokeByID(c *fiber.Ctx, jokeID string) (models.Joke, error) {
 	cacheKey := utils.CacheKeyPrefixJoke + jokeID
 
-	if cached, ok := wrapper.ReadCacheIfAllowed(c, cacheKey); ok {
+	if cached, ok := wrapper.ReadCacheIfAllowed[models.Joke](c, cacheKey); ok {
 		return cached, nil
 	}
 
-	joke, err := helpers.GetJokeByID(c, jokeID)
+	joke, err := s.repository.GetByID(jokeID)
 	if err != nil {
-		return nil, err
+		if err == helpers.ErrJokeNotFound {
+			config.LogInfo(c, "Joke not found", "id", jokeID)
+		}
+		return models.Joke{}, err
 	}
 
 	_ = wrapper.WriteCacheIfAllowed(c, cacheKey, joke)
 
 	return joke, nil
 }
+
+func (s *JokeService) GetCategories(c *fiber.Ctx) models.CategoryList {
+	categories := s.repository.GetCategories()
+
+	return models.CategoryList{
+		Categories: categories,
+		Total:      len(categories),
+	}
+}
+
+func (s *JokeService) SearchJokes(c *fiber.Ctx, query string, matchAll bool, offset, limit int) models.SearchResponse {
+	results, total := s.repository.Search(query, matchAll, offset, limit)
+
+	config.LogInfo(c, "Jokes searched", "query", query, "total", total)
+
+	return models.SearchResponse{
+		Query:   query,
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Results: results,
+	}
+}
+
+func (s *JokeService) ListJokes(c *fiber.Ctx, cursor string, limit int) (models.JokeList, error) {
+	afterID := ""
+	if cursor != "" {
+		var err error
+		if afterID, err = helpers.DecodeCursor(cursor); err != nil {
+			return models.JokeList{}, err
+		}
+	}
+
+	jokes, nextID := s.repository.List(afterID, limit)
+
+	list := models.JokeList{
+		Data:  jokes,
+		Total: s.repository.Count(),
+		Limit: limit,
+	}
+	if nextID != "" {
+		list.NextCursor = helpers.EncodeCursor(nextID)
+	}
+
+	return list, nil
+}
+
+func (s *JokeService) GetJokesBatch(c *fiber.Ctx, jokeIDs []string) models.BatchResponse {
+	jokes, missing := s.repository.GetByIDs(jokeIDs)
+
+	config.LogInfo(c, "Batch jokes requested", "requested", len(jokeIDs), "found", len(jokes), "missing", len(missing))
+
+	return models.BatchResponse{
+		Jokes:   jokes,
+		Missing: missing,
+	}
+}
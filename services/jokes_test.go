@@ -0,0 +1,104 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/utils"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadEnvVars()
+	config.InitializeLogger(fiber.New())
+	os.Exit(m.Run())
+}
+
+func newTestJokeService(t *testing.T, randomSeed int64) *JokeService {
+	t.Helper()
+
+	repo := helpers.NewMemoryJokeRepository()
+	records := [][]string{{"id", "joke"}}
+	for _, id := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		records = append(records, []string{id, "joke " + id})
+	}
+	if err := repo.Load(records); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return NewJokeServiceWithRandom(repo, rand.New(rand.NewSource(randomSeed)))
+}
+
+func newTestCtx(t *testing.T) *fiber.Ctx {
+	t.Helper()
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	t.Cleanup(func() { app.ReleaseCtx(c) })
+	return c
+}
+
+// picks returns the seeds and joke IDs of a few single and multi-joke picks
+func picks(t *testing.T, service *JokeService) []string {
+	t.Helper()
+	c := newTestCtx(t)
+
+	var out []string
+	for range 3 {
+		joke, selection, err := service.GetRandomJoke(c, "", nil)
+		if err != nil {
+			t.Fatalf("GetRandomJoke: %v", err)
+		}
+		out = append(out, selection.Seed, joke.ID)
+	}
+	list, err := service.GetRandomJokes(c, "", nil, 3)
+	if err != nil {
+		t.Fatalf("GetRandomJokes: %v", err)
+	}
+	out = append(out, list.Seed)
+	for _, joke := range list.Data {
+		out = append(out, joke.ID)
+	}
+	return out
+}
+
+func TestSeededRandomSourceIsDeterministic(t *testing.T) {
+	config.AppConfig.RandomMode = utils.RandomModeUniform
+
+	first := picks(t, newTestJokeService(t, 42))
+	second := picks(t, newTestJokeService(t, 42))
+	other := picks(t, newTestJokeService(t, 7))
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("random source 42 gave %v, then %v", first, second)
+		}
+	}
+	if first[0] == other[0] {
+		t.Errorf("random sources 42 and 7 both generated seed %s", first[0])
+	}
+	if first[0] == first[2] {
+		t.Errorf("consecutive picks reused seed %s", first[0])
+	}
+}
+
+func TestExplicitSeedReplaysPick(t *testing.T) {
+	service := newTestJokeService(t, 42)
+	c := newTestCtx(t)
+
+	joke, selection, err := service.GetRandomJoke(c, "", nil)
+	if err != nil {
+		t.Fatalf("GetRandomJoke: %v", err)
+	}
+
+	// A differently seeded service replays the pick from the returned seed
+	replay, replaySelection, err := newTestJokeService(t, 7).GetRandomJoke(c, selection.Seed, nil)
+	if err != nil {
+		t.Fatalf("GetRandomJoke: %v", err)
+	}
+	if replay.ID != joke.ID || replaySelection != selection {
+		t.Errorf("seed %s replayed %s (%+v), want %s (%+v)", selection.Seed, replay.ID, replaySelection, joke.ID, selection)
+	}
+}
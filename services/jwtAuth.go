@@ -0,0 +1,65 @@
+package services
+
+import (
+	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/models"
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// tokenLookup caches the outcome of validating the request's bearer token
+type tokenLookup struct {
+	token     *models.AuthToken
+	presented bool
+	err       error
+}
+
+// ResolveToken returns the validated JWT bearer token, or nil
+func ResolveToken(c *fiber.Ctx) *models.AuthToken {
+	return lookupToken(c).token
+}
+
+// HasToken reports whether the request is authenticated by a valid JWT
+func HasToken(c *fiber.Ctx) bool {
+	return ResolveToken(c) != nil
+}
+
+func lookupToken(c *fiber.Ctx) tokenLookup {
+	if !config.AppConfig.JWTEnabled {
+		return tokenLookup{}
+	}
+
+	if lookup, ok := c.Locals(utils.LocalsToken).(tokenLookup); ok {
+		return lookup
+	}
+
+	var lookup tokenLookup
+	if raw := bearerToken(c); helpers.LooksLikeJWT(raw) {
+		lookup.presented = true
+		lookup.token, lookup.err = helpers.ParseToken(raw)
+	}
+	c.Locals(utils.LocalsToken, lookup)
+	if lookup.token != nil {
+		c.Locals(utils.LocalsSubject, lookup.token.Subject)
+	}
+
+	return lookup
+}
+
+// RequireScope returns middleware rejecting requests whose JWT lacks scope
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := ResolveToken(c)
+		if token == nil || slices.Contains(token.Scopes, scope) {
+			return c.Next()
+		}
+
+		config.LogInfo(c, "Token is missing the required scope", utils.JSONKeyScope, scope)
+		c.Set(utils.HeaderWWWAuth, `Bearer error="insufficient_scope", scope="`+scope+`"`)
+		return problems.Forbidden(utils.ErrMsgInsufficientScope).With(utils.JSONKeyScope, scope)
+	}
+}
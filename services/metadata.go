@@ -2,7 +2,10 @@ package services
 
 import (
 	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/middleware"
 	"jokes-provider/models"
+	"jokes-provider/utils"
 	"time"
 )
 
@@ -34,22 +37,27 @@ func (s *MetadataService) GetMetadata() models.Metadata {
 			DisableColors: config.AppConfig.LogDisableColors,
 		},
 		Cache: models.CacheInfo{
-			Enabled: config.CacheConfig.CacheEnabled,
-			URL:     config.CacheConfig.CacheURL,
-			TTL:     config.CacheConfig.CacheTTL,
+			Enabled:   config.CacheConfig.CacheEnabled,
+			URL:       config.CacheConfig.CacheURL,
+			TTL:       config.CacheConfig.CacheTTL,
+			Readiness: config.CacheConfig.CacheReadiness,
+			Breaker:   middleware.GetCacheBreakerInfo(),
 		},
 		Files: models.FilesInfo{
 			JokesPath: config.AppConfig.JokesFilePath,
 		},
+		Dataset: helpers.GetDatasetInfo(),
+		Random:  getRandomInfo(),
 		Headers: models.HeadersInfo{
 			IPHeaderName:      config.AppConfig.IPHeaderName,
+			TrustedProxies:    helpers.SplitList(config.AppConfig.TrustedProxies),
 			CountryHeaderName: config.AppConfig.CountryHeaderName,
+			SessionHeaderName: config.AppConfig.SessionHeaderName,
 		},
-		RateLimiter: models.RateLimiterInfo{
-			Enabled:     config.AppConfig.RateLimitEnabled,
-			MaxRequests: config.AppConfig.RateLimitMaxRequests,
-			Duration:    config.AppConfig.RateLimitDuration,
-		},
+		RateLimiter: getRateLimiterInfo(),
+		Auth:        getAuthInfo(),
+		Metrics:     getMetricsInfo(),
+		Tracing:     getTracingInfo(),
 		Fiber: models.FiberInfo{
 			Prefork:       config.FiberConfig.Prefork,
 			CaseSensitive: config.FiberConfig.CaseSensitive,
@@ -57,3 +65,101 @@ func (s *MetadataService) GetMetadata() models.Metadata {
 		},
 	}
 }
+
+// getRandomInfo describes how /v1/jokes/random selects jokes
+func getRandomInfo() models.RandomInfo {
+	if !IsStickyRandom() {
+		return models.RandomInfo{
+			Mode:        utils.RandomModeUniform,
+			Description: "A new seed is generated on every request and the joke is picked uniformly from it; pass the returned X-Random-Seed back as ?seed= to replay a pick",
+		}
+	}
+
+	description := "Each client (by IP) keeps the same random seed, and therefore the same joke, until the cache entry expires"
+	if config.AppConfig.RandomStickyKey == utils.RandomStickyKeySession {
+		description = "Each session (by " + config.AppConfig.SessionHeaderName + " header) keeps the same random seed, and therefore the same joke, until the cache entry expires; requests without a session get a new seed every time"
+	}
+
+	return models.RandomInfo{
+		Mode:        utils.RandomModeSticky,
+		StickyKey:   config.AppConfig.RandomStickyKey,
+		StickyTTL:   config.CacheConfig.CacheTTL,
+		Description: description,
+	}
+}
+
+// getRateLimiterInfo describes the default limit and the effective policies
+func getRateLimiterInfo() models.RateLimiterInfo {
+	info := models.RateLimiterInfo{
+		Enabled:     config.AppConfig.RateLimitEnabled,
+		MaxRequests: config.AppConfig.RateLimitMaxRequests,
+		Duration:    config.AppConfig.RateLimitDuration,
+		Algorithm:   config.AppConfig.RateLimitAlgorithm,
+		Burst:       config.AppConfig.RateLimitBurst,
+		Storage:     RateLimitStorage(),
+	}
+
+	if info.Enabled {
+		policy := helpers.GetRateLimitPolicy()
+		info.PolicyFile = config.AppConfig.RateLimitPolicyFile
+		info.ExemptRoutes = policy.ExemptRoutes
+		info.ExemptNetworks = policy.ExemptNetworks
+		info.InternalNetworks = policy.InternalNetworks
+		info.Policies = policy.Policies
+	}
+
+	return info
+}
+
+// getAuthInfo describes how requests are authenticated, without revealing keys
+func getAuthInfo() models.AuthInfo {
+	info := models.AuthInfo{
+		APIKeysEnabled:   config.AppConfig.APIKeysEnabled,
+		APIKeyHeaderName: config.AppConfig.APIKeyHeaderName,
+		JWTEnabled:       config.AppConfig.JWTEnabled,
+		AdminEnabled: config.AppConfig.APIKeysEnabled || config.AppConfig.JWTEnabled ||
+			(config.AppConfig.AdminUsername != "" && config.AppConfig.AdminPassword != ""),
+	}
+
+	if info.APIKeysEnabled {
+		info.APIKeyCount = len(helpers.GetAPIKeys())
+	}
+	if info.JWTEnabled {
+		info.JWTAlgorithms = helpers.SplitList(config.AppConfig.JWTAlgorithms)
+		info.JWTIssuer = config.AppConfig.JWTIssuer
+		info.JWTAudience = config.AppConfig.JWTAudience
+	}
+	if info.APIKeysEnabled || info.JWTEnabled {
+		info.AnonymousRoutes = helpers.SplitList(config.AppConfig.AnonymousRoutes)
+	}
+
+	return info
+}
+
+// getMetricsInfo describes where the Prometheus metrics are served
+func getMetricsInfo() models.MetricsInfo {
+	if !config.AppConfig.MetricsEnabled {
+		return models.MetricsInfo{}
+	}
+
+	return models.MetricsInfo{
+		Enabled: true,
+		Path:    config.AppConfig.MetricsPath,
+		Port:    config.AppConfig.MetricsPort,
+	}
+}
+
+// getTracingInfo describes where traces are exported
+func getTracingInfo() models.TracingInfo {
+	if !config.AppConfig.TracingEnabled {
+		return models.TracingInfo{}
+	}
+
+	return models.TracingInfo{
+		Enabled:     true,
+		Exporter:    config.AppConfig.TracingExporter,
+		Endpoint:    config.AppConfig.TracingEndpoint,
+		SampleRatio: config.AppConfig.TracingSampleRatio,
+		ServiceName: config.AppConfig.TracingServiceName,
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"jokes-provider/config"
+	"jokes-provider/metrics"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// metricsServer serves the metrics on METRICS_PORT, apart from the API
+var metricsServer *http.Server
+
+// SetupMetrics registers the metrics endpoint on the API unless METRICS_PORT is set
+func SetupMetrics(app *fiber.App) {
+	if !config.AppConfig.MetricsEnabled || config.AppConfig.MetricsPort != "" {
+		return
+	}
+
+	app.Get(config.AppConfig.MetricsPath, metrics.Handler())
+}
+
+// StartMetricsServer binds METRICS_PORT and serves the metrics in the background
+func StartMetricsServer() error {
+	if !config.AppConfig.MetricsEnabled || config.AppConfig.MetricsPort == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", ":"+config.AppConfig.MetricsPort)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.AppConfig.MetricsPath, metrics.HTTPHandler())
+	metricsServer = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := metricsServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			config.LogError(nil, "Metrics server stopped", "error", err.Error())
+		}
+	}()
+
+	config.LogInfo(nil, "Metrics server started", "port", config.AppConfig.MetricsPort, "path", config.AppConfig.MetricsPath)
+	return nil
+}
+
+// StopMetricsServer stops the metrics server, if running
+func StopMetricsServer() error {
+	if metricsServer == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return metricsServer.Shutdown(ctx)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// responseMediaTypes maps the offered media types to formats, JSON first as the default
+var responseMediaTypes = []struct {
+	mediaType string
+	format    string
+}{
+	{utils.MIMEApplicationJSON, utils.FormatJSON},
+	{utils.MIMETextPlain, utils.FormatText},
+	{utils.MIMEApplicationXML, utils.FormatXML},
+	{utils.MIMETextXML, utils.FormatXML},
+	{utils.MIMEApplicationYAML, utils.FormatYAML},
+	{utils.MIMETextYAML, utils.FormatYAML},
+	{utils.MIMETextCSV, utils.FormatCSV},
+	{utils.MIMETextHTML, utils.FormatHTML},
+}
+
+// ResponseFormats lists the formats a format query parameter can ask for
+var ResponseFormats = []string{utils.FormatJSON, utils.FormatText, utils.FormatXML, utils.FormatYAML, utils.FormatCSV, utils.FormatHTML}
+
+// NegotiateFormat picks the response format from the format query parameter
+// or the Accept header, answering 406 when none is supported
+func NegotiateFormat() fiber.Handler {
+	offers := make([]string, len(responseMediaTypes))
+	for i, offer := range responseMediaTypes {
+		offers[i] = offer.mediaType
+	}
+
+	return func(c *fiber.Ctx) error {
+		// The same URL has several representations
+		c.Vary(utils.HeaderAccept)
+
+		format := ""
+		if requested := c.Query(utils.QueryFormat); requested != "" {
+			for _, supported := range ResponseFormats {
+				if strings.EqualFold(requested, supported) {
+					format = supported
+					break
+				}
+			}
+		} else if accepted := acceptedOffer(c.Get(utils.HeaderAccept), offers); accepted != "" {
+			for _, offer := range responseMediaTypes {
+				if offer.mediaType == accepted {
+					format = offer.format
+					break
+				}
+			}
+		}
+
+		if format == "" {
+			return problems.NotAcceptable(utils.ErrMsgNotAcceptable).With(utils.JSONKeySupported, offers)
+		}
+
+		c.Locals(utils.LocalsFormat, format)
+		return c.Next()
+	}
+}
+
+// acceptRange is one media range of an Accept header
+type acceptRange struct {
+	mediaType string
+	quality   float64
+	order     int
+}
+
+// specificity ranks exact types above type/* and */*
+func (r acceptRange) specificity() int {
+	switch {
+	case r.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(r.mediaType, "/*"):
+		return 1
+	}
+	return 2
+}
+
+func (r acceptRange) matches(mediaType string) bool {
+	if r.mediaType == "*/*" || r.mediaType == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(r.mediaType, "*")
+	return ok && strings.HasPrefix(mediaType, prefix)
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for i, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		r := acceptRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), quality: 1, order: i}
+		if r.mediaType == "" {
+			continue
+		}
+		if r.mediaType == "*" {
+			r.mediaType = "*/*"
+		}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// acceptedOffer returns the offer the Accept header prefers, or "" if none is
+// acceptable. An offer takes the quality of its most specific range, so q=0
+// excludes it even when a wildcard matches.
+func acceptedOffer(header string, offers []string) string {
+	ranges := parseAccept(header)
+	if len(ranges) == 0 {
+		return offers[0]
+	}
+
+	best, bestRange := "", acceptRange{}
+	for _, offer := range offers {
+		matched, found := acceptRange{}, false
+		for _, r := range ranges {
+			if r.matches(offer) && (!found || r.specificity() > matched.specificity()) {
+				matched, found = r, true
+			}
+		}
+		if !found || matched.quality <= 0 {
+			continue
+		}
+		if best == "" || matched.quality > bestRange.quality ||
+			(matched.quality == bestRange.quality && (matched.specificity() > bestRange.specificity() ||
+				matched.specificity() == bestRange.specificity() && matched.order < bestRange.order)) {
+			best, bestRange = offer, matched
+		}
+	}
+	return best
+}
+
+// ResponseFormat returns the format negotiated for the request, JSON if none was
+func ResponseFormat(c *fiber.Ctx) string {
+	if format, ok := c.Locals(utils.LocalsFormat).(string); ok {
+		return format
+	}
+	return utils.FormatJSON
+}
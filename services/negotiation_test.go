@@ -0,0 +1,74 @@
+package services
+
+import (
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: problems.Handler})
+	app.Get("/", NegotiateFormat(), func(c *fiber.Ctx) error {
+		return c.SendString(ResponseFormat(c))
+	})
+
+	tests := []struct {
+		name   string
+		accept string
+		query  string
+		want   string
+	}{
+		{"no accept", "", "", utils.FormatJSON},
+		{"any", "*/*", "", utils.FormatJSON},
+		{"bare wildcard", "*", "", utils.FormatJSON},
+		{"exact", "text/csv", "", utils.FormatCSV},
+		{"case insensitive", "Application/YAML", "", utils.FormatYAML},
+		{"type wildcard", "text/*", "", utils.FormatText},
+		{"exact beats type wildcard", "text/*;q=0.5, text/csv", "", utils.FormatCSV},
+		{"highest quality", "application/json;q=0.4, text/csv;q=0.8", "", utils.FormatCSV},
+		{"parameters ignored", "application/json; charset=utf-8; q=0.5, text/csv;q=0.4", "", utils.FormatJSON},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "", utils.FormatHTML},
+		{"first range wins a tie", "text/csv, application/json", "", utils.FormatCSV},
+		{"q=0 excludes wildcard match", "application/json;q=0, */*;q=0.1", "", utils.FormatText},
+		{"q=0 type excluded", "text/*;q=0, */*", "", utils.FormatJSON},
+		{"q=0 exact within type wildcard", "text/*, text/plain;q=0", "", utils.FormatXML},
+		{"format parameter wins", "text/csv", "yaml", utils.FormatYAML},
+		{"unsupported", "image/png", "", ""},
+		{"only excluded", "application/json;q=0", "", ""},
+		{"everything excluded", "*/*;q=0", "", ""},
+		{"unknown format parameter", "", "pdf", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/"
+			if tt.query != "" {
+				target += "?format=" + tt.query
+			}
+			req := httptest.NewRequest(fiber.MethodGet, target, nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			body := make([]byte, 16)
+			n, _ := resp.Body.Read(body)
+
+			if tt.want == "" {
+				if resp.StatusCode != fiber.StatusNotAcceptable {
+					t.Errorf("Accept %q = %d %s, want 406", tt.accept, resp.StatusCode, body[:n])
+				}
+				return
+			}
+			if resp.StatusCode != fiber.StatusOK || string(body[:n]) != tt.want {
+				t.Errorf("Accept %q = %d %s, want %s", tt.accept, resp.StatusCode, body[:n], tt.want)
+			}
+		})
+	}
+}
@@ -1,39 +1,159 @@
 package services
 
 import (
+	"fmt"
 	"jokes-provider/config"
+	"jokes-provider/helpers"
+	"jokes-provider/metrics"
+	"jokes-provider/middleware"
+	"jokes-provider/models"
+	"jokes-provider/problems"
 	"jokes-provider/utils"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 )
 
-func SetupRateLimiter() fiber.Handler {
-	if config.AppConfig.RateLimitEnabled == false {
+// rateLimitStore keeps the limiter counters, nil while rate limiting is disabled
+var rateLimitStore *middleware.RateLimitStore
+
+// SetupRateLimiter returns the middleware limiting requests with the policy
+// matching each request's route and client tier
+func SetupRateLimiter() (fiber.Handler, error) {
+	if !config.AppConfig.RateLimitEnabled {
 		config.LogInfo(nil, "Rate limiter is disabled")
 		return func(c *fiber.Ctx) error {
 			return c.Next()
+		}, nil
+	}
+
+	rateLimitStore = middleware.GetRateLimitStore()
+
+	policy := helpers.GetRateLimitPolicy()
+	limiters := make(map[string]*helpers.RateLimiter, len(policy.Policies))
+	for _, p := range policy.Policies {
+		if p.Exempt {
+			continue
+		}
+		limiter, err := newPolicyLimiter(p, p.MaxRequests)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		limiters[p.Name] = limiter
+		config.LogInfo(nil, "Rate limit policy", "policy", p.Name, "routes", strings.Join(p.Routes, ","), "tiers", strings.Join(p.Tiers, ","),
+			"algorithm", p.Algorithm, "max_requests", p.MaxRequests, "window", p.Window)
+	}
+
+	// Keys with their own limit replace the maximum of every policy
+	perKey := make(map[string]*helpers.RateLimiter)
+	for _, key := range helpers.GetAPIKeys() {
+		if key.RateLimit <= 0 {
+			continue
+		}
+		for _, p := range policy.Policies {
+			if p.Exempt {
+				continue
+			}
+			limiter, err := newPolicyLimiter(p, key.RateLimit)
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+			}
+			perKey[p.Name+"|"+key.Name] = limiter
 		}
+		config.LogInfo(nil, "Rate limit override for API key", "key_name", key.Name, "max_requests", key.RateLimit)
 	}
-	expiration := utils.GetDurationFromEnv(config.AppConfig.RateLimitDuration, 1*time.Minute)
-	maxRequests := config.AppConfig.RateLimitMaxRequests
 
-	config.LogInfo(nil, "Rate limiter initialized", "max_requests", maxRequests, "expiration(ns)", expiration)
+	config.LogInfo(nil, "Rate limiter initialized", "policies", len(policy.Policies), "exempt_routes", strings.Join(policy.ExemptRoutes, ","),
+		"exempt_networks", strings.Join(policy.ExemptNetworks, ","), "storage", RateLimitStorage())
+
+	return func(c *fiber.Ctx) error {
+		path, ip := c.Path(), config.ClientIP(c)
+		if helpers.IsRateLimitExempt(path, ip) {
+			return c.Next()
+		}
+
+		tier, identity := rateLimitTier(c, ip)
+		p := helpers.MatchRateLimitPolicy(path, tier)
+		if p == nil || p.Exempt {
+			return c.Next()
+		}
 
-	return limiter.New(limiter.Config{
-		Max:        maxRequests,
-		Expiration: expiration,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			ip := c.Get(config.AppConfig.IPHeaderName)
-			if ip == "" {
-				ip = c.IP()
+		limiter := limiters[p.Name]
+		if key := ResolveAPIKey(c); key != nil {
+			if keyLimiter, ok := perKey[p.Name+"|"+key.Name]; ok {
+				limiter = keyLimiter
 			}
-			return ip
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			config.LogInfo(c, "Rate limit exceeded")
-			return c.SendStatus(fiber.StatusTooManyRequests)
-		},
-	})
+		}
+
+		decision, err := limiter.Take(p.Name+":"+identity, time.Now())
+		if err != nil {
+			// Failing open keeps the API up when the counters cannot be read
+			config.LogError(c, "Error applying rate limit", "error", err.Error())
+			return c.Next()
+		}
+
+		setRateLimitHeaders(c, decision)
+		if !decision.Allowed {
+			retryAfter := ceilSeconds(decision.RetryAfter)
+			config.LogInfo(c, "Rate limit exceeded", "policy", p.Name, "tier", tier, "retry_after", retryAfter)
+			metrics.ObserveRateLimitRejection(p.Name, tier)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return problems.RateLimited(retryAfter)
+		}
+
+		return c.Next()
+	}, nil
+}
+
+// newPolicyLimiter creates the limiter of a policy with maxRequests
+func newPolicyLimiter(policy models.RateLimitPolicy, maxRequests int) (*helpers.RateLimiter, error) {
+	window, err := time.ParseDuration(policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	burst := policy.Burst
+	if burst > 0 && maxRequests != policy.MaxRequests {
+		burst = max(1, maxRequests*burst/policy.MaxRequests)
+	}
+	return helpers.NewRateLimiter(policy.Algorithm, maxRequests, window, burst, rateLimitStore)
+}
+
+// RateLimitStorage names where the limiter keeps its counters, empty when disabled
+func RateLimitStorage() string {
+	switch {
+	case rateLimitStore == nil:
+		return ""
+	case rateLimitStore.IsShared():
+		return utils.RateLimitStorageRedis
+	}
+	return utils.RateLimitStorageMemory
+}
+
+// rateLimitTier returns the client's tier and the identity its requests are counted under
+func rateLimitTier(c *fiber.Ctx, ip string) (string, string) {
+	if key := ResolveAPIKey(c); key != nil {
+		return utils.RateLimitTierKeyed, "apikey:" + key.Name
+	}
+	if token := ResolveToken(c); token != nil && token.Subject != "" {
+		return utils.RateLimitTierKeyed, "subject:" + token.Subject
+	}
+	if helpers.IsInternalNetwork(ip) {
+		return utils.RateLimitTierInternal, ip
+	}
+	return utils.RateLimitTierAnonymous, ip
+}
+
+// setRateLimitHeaders sets the RateLimit headers of the IETF httpapi-ratelimit-headers draft
+func setRateLimitHeaders(c *fiber.Ctx, decision helpers.RateLimitDecision) {
+	c.Set(utils.HeaderRateLimitLimit, strconv.Itoa(decision.Limit))
+	c.Set(utils.HeaderRateLimitRemaining, strconv.Itoa(decision.Remaining))
+	c.Set(utils.HeaderRateLimitReset, strconv.Itoa(ceilSeconds(decision.Reset)))
+}
+
+// ceilSeconds rounds up, so clients never retry early
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
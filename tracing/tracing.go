@@ -0,0 +1,227 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jokes-provider/problems"
+	"jokes-provider/utils"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes set on spans beyond the semantic conventions
+const (
+	AttributeCacheKey    = attribute.Key("cache.key")
+	AttributeCacheHit    = attribute.Key("cache.hit")
+	AttributeCacheSkip   = attribute.Key("cache.skip")
+	AttributeJokesSource = attribute.Key("jokes.source")
+	AttributeJokesPath   = attribute.Key("jokes.path")
+	AttributeJokesCount  = attribute.Key("jokes.count")
+)
+
+// instrumentationName identifies the spans created by this service
+const instrumentationName = "jokes-provider"
+
+// ErrUnknownExporter is returned when TRACING_EXPORTER names an unsupported exporter
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Settings configure the tracer provider
+type Settings struct {
+	Exporter       string
+	Endpoint       string
+	SampleRatio    float64
+	ServiceName    string
+	ServiceVersion string
+}
+
+// provider is set once tracing is initialized; spans are no-ops until then
+var provider *sdktrace.TracerProvider
+
+// tracer creates the spans. The global provider is a no-op unless Init ran.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Init sets up the exporter and the global tracer provider with W3C propagation
+func Init(settings Settings) error {
+	exporter, err := newExporter(settings)
+	if err != nil {
+		return err
+	}
+
+	resource, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(settings.ServiceName),
+		semconv.ServiceVersion(settings.ServiceVersion),
+	))
+	if err != nil {
+		return err
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource),
+		// Follow the caller's sampling decision, so a trace is never half recorded
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(settings.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return nil
+}
+
+// newExporter creates the span exporter; OTLP falls back to the OTEL_EXPORTER_OTLP_* variables
+func newExporter(settings Settings) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+
+	switch strings.ToLower(settings.Exporter) {
+	case utils.TracingExporterOTLPHTTP:
+		var options []otlptracehttp.Option
+		if settings.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(settings.Endpoint))
+		}
+		return otlptracehttp.New(ctx, options...)
+	case utils.TracingExporterOTLPGRPC:
+		var options []otlptracegrpc.Option
+		if settings.Endpoint != "" {
+			options = append(options, otlptracegrpc.WithEndpointURL(settings.Endpoint))
+		}
+		return otlptracegrpc.New(ctx, options...)
+	case utils.TracingExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, settings.Exporter)
+}
+
+// Shutdown exports the spans still buffered and stops the tracer provider
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+// Middleware starts a server span for every request in its user context,
+// continuing an incoming traceparent
+func Middleware() fiber.Handler {
+	propagator := otel.GetTextMapPropagator()
+
+	return func(c *fiber.Ctx) error {
+		ctx := propagator.Extract(c.UserContext(), headerCarrier{c})
+		ctx, span := tracer().Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = problems.StatusCode(err)
+		}
+
+		// Global middleware is mounted on "/"
+		if route := c.Route().Path; route != "/" || c.Path() == "/" {
+			span.SetName(c.Method() + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if requestID := c.GetRespHeader(fiber.HeaderXRequestID); requestID != "" {
+			span.SetAttributes(attribute.String("http.request.id", requestID))
+		}
+		// Client errors are expected answers, not failures of the span
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+			if err != nil {
+				span.RecordError(err)
+			}
+		}
+
+		return err
+	}
+}
+
+// Span is a child span of a request, its current span while open
+type Span struct {
+	trace.Span
+	c      *fiber.Ctx
+	parent context.Context
+}
+
+// Start starts a child of the request's current span, or a new trace without a request
+func Start(c *fiber.Ctx, name string, attributes ...attribute.KeyValue) *Span {
+	parent := context.Background()
+	if c != nil {
+		parent = c.UserContext()
+	}
+
+	ctx, span := tracer().Start(parent, name, trace.WithAttributes(attributes...))
+	if c != nil {
+		c.SetUserContext(ctx)
+	}
+	return &Span{Span: span, c: c, parent: parent}
+}
+
+// StartRedis starts a span for one Redis command on key
+func StartRedis(c *fiber.Ctx, operation, key string) *Span {
+	return Start(c, "redis "+operation,
+		semconv.DBSystemNameRedis,
+		semconv.DBOperationName(operation),
+		AttributeCacheKey.String(key),
+	)
+}
+
+// Fail records err on the span and marks it as failed
+func (s *Span) Fail(err error) {
+	s.RecordError(err)
+	s.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span and makes its parent the request's current span again
+func (s *Span) End() {
+	s.Span.End()
+	if s.c != nil {
+		s.c.SetUserContext(s.parent)
+	}
+}
+
+// headerCarrier reads the propagation headers of a Fiber request
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	headers := h.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
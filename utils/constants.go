@@ -4,33 +4,181 @@ package utils
 const (
 	HeaderCacheControl = "Cache-Control"
 	HeaderContentType  = "Content-Type"
+	HeaderLink         = "Link"
+	HeaderTotalCount   = "X-Total-Count"
+	HeaderExpires      = "Expires"
+	HeaderRandomSeed   = "X-Random-Seed"
+	HeaderDatasetVer   = "X-Dataset-Version"
+	HeaderSessionLeft  = "X-Session-Remaining"
+	HeaderLocation     = "Location"
+	HeaderWWWAuth      = "WWW-Authenticate"
+	HeaderAuthorize    = "Authorization"
+	HeaderVary         = "Vary"
+	HeaderAccept       = "Accept"
+
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// Response Formats, chosen with the Accept header or the format query parameter
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+	FormatXML  = "xml"
+	FormatYAML = "yaml"
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+)
+
+// Media Types
+const (
+	MIMEApplicationJSON = "application/json"
+	MIMEApplicationXML  = "application/xml"
+	MIMEApplicationYAML = "application/yaml"
+	MIMETextPlain       = "text/plain"
+	MIMETextXML         = "text/xml"
+	MIMETextYAML        = "text/yaml"
+	MIMETextCSV         = "text/csv"
+	MIMETextHTML        = "text/html"
+
+	MIMEApplicationProblemJSON = "application/problem+json"
 )
 
 // Cache Control Values
 const (
 	CacheControlNoCache = "no-cache"
+	CacheControlNoStore = "no-store"
+	CacheControlPublic  = "public"
 )
 
 // Cache Key Prefixes
 const (
-	CacheKeyPrefixJoke = "joke:"
+	CacheKeyPrefixJoke      = "joke:"
+	CacheKeyPrefixSticky    = "random:sticky:"
+	CacheKeyPrefixSession   = "session:deck:"
+	CacheKeyPrefixRateLimit = "ratelimit:"
+)
+
+// Rate Limiting Algorithms
+const (
+	RateLimitFixedWindow   = "fixed_window"
+	RateLimitSlidingWindow = "sliding_window"
+	RateLimitSlidingLog    = "sliding_log"
+	RateLimitTokenBucket   = "token_bucket"
+)
+
+// Rate Limiting Client Tiers
+const (
+	RateLimitTierAnonymous = "anonymous"
+	RateLimitTierKeyed     = "keyed"
+	RateLimitTierInternal  = "internal"
+
+	RateLimitDefaultPolicy = "default"
+)
+
+// TrustedProxiesNone disables forwarding headers
+const TrustedProxiesNone = "none"
+
+// Rate Limiter Storage
+const (
+	RateLimitStorageRedis  = "redis"
+	RateLimitStorageMemory = "memory"
+)
+
+// Cache Readiness Policies
+const (
+	CacheReadinessRequired = "required"
+	CacheReadinessOptional = "optional"
+)
+
+// Cache Circuit Breaker States
+const (
+	CacheBreakerClosed   = "closed"
+	CacheBreakerOpen     = "open"
+	CacheBreakerDisabled = "disabled"
+)
+
+// Readiness Statuses
+const (
+	ReadinessReady    = "ready"
+	ReadinessDegraded = "degraded"
+	ReadinessNotReady = "not_ready"
 )
 
-// CSV Column Names
+// Tracing Exporters
 const (
-	CSVColumnID = "ID"
+	TracingExporterOTLPHTTP = "otlp_http"
+	TracingExporterOTLPGRPC = "otlp_grpc"
+	TracingExporterStdout   = "stdout"
+)
+
+// Random Selection Modes
+const (
+	RandomModeUniform = "uniform"
+	RandomModeSticky  = "sticky"
+
+	RandomStickyKeyClient  = "client"
+	RandomStickyKeySession = "session"
+)
+
+// Jokes Data Sources
+const (
+	JokesSourceCSV    = "csv"
+	JokesSourceJSONL  = "jsonl"
+	JokesSourceSQLite = "sqlite"
+	JokesSourceDir    = "dir"
+
+	FileExtCSV   = ".csv"
+	FileExtJSONL = ".jsonl"
+)
+
+// Request Locals
+const (
+	LocalsAPIKey     = "api_key"
+	LocalsAPIKeyName = "api_key_name"
+	LocalsToken      = "auth_token"
+	LocalsSubject    = "subject"
+	LocalsClientIP   = "client_ip"
+	LocalsFormat     = "response_format"
+)
+
+// Authentication Schemes
+const (
+	AuthSchemeBearer = "Bearer "
+)
+
+// Token Scopes
+const (
+	ScopeJokesRead    = "jokes:read"
+	ScopeJokesWrite   = "jokes:write"
+	ScopeMetadataRead = "metadata:read"
+)
+
+// Default CSV column names, overridable with JOKES_COLUMN_*
+const (
+	CSVColumnID       = "ID"
+	CSVColumnJoke     = "Joke"
+	CSVColumnCategory = "Category"
 )
 
 // API Route Prefixes
 const (
 	APIVersionV1       = "/v1"
 	RouteJokes         = "/jokes"
+	ListJokesEndpoint  = "/"
 	RandomJokeEndpoint = "/random"
 	RouteHealth        = "/health"
 	JokeByIDEndpoint   = "/:id"
+	CategoriesEndpoint = "/categories"
+	SearchEndpoint     = "/search"
+	BatchEndpoint      = "/batch"
+	DailyEndpoint      = "/daily"
 	MetadataEndpoint   = "/metadata"
 	LivenessEndpoint   = "/liveness"
 	ReadinessEndpoint  = "/readiness"
+	RouteAdmin         = "/admin"
+	ImportEndpoint     = "/import"
 )
 
 // Route Parameters
@@ -38,17 +186,114 @@ const (
 	ParamID = "id"
 )
 
+// Query Parameters
+const (
+	QueryCategory = "category"
+	QuerySearch   = "q"
+	QueryMatch    = "match"
+	QueryLimit    = "limit"
+	QueryOffset   = "offset"
+	QueryCursor   = "cursor"
+	QueryCount    = "count"
+	QueryTimezone = "tz"
+	QueryDate     = "date"
+	QuerySeed     = "seed"
+	QueryFormat   = "format"
+)
+
+// Search Match Modes
+const (
+	SearchMatchAll = "all"
+	SearchMatchAny = "any"
+)
+
+// Pagination
+const (
+	SearchDefaultLimit = 10
+	SearchMaxLimit     = 50
+	ListDefaultLimit   = 20
+	ListMaxLimit       = 100
+)
+
+// Random Selection Limits
+const (
+	MaxSeedLength      = 128
+	MaxSessionIDLength = 256
+)
+
+// Problem Types, the last segment of the RFC 7807 type URI
+const (
+	ProblemTypeBase                = "/problems/"
+	ProblemTypeBlank               = "about:blank"
+	ProblemTypeNotFound            = "not-found"
+	ProblemTypeValidation          = "validation"
+	ProblemTypeUnauthorized        = "unauthorized"
+	ProblemTypeForbidden           = "forbidden"
+	ProblemTypeConflict            = "conflict"
+	ProblemTypeNotAcceptable       = "not-acceptable"
+	ProblemTypeRateLimited         = "rate-limited"
+	ProblemTypeUpstreamUnavailable = "upstream-unavailable"
+	ProblemTypeInternal            = "internal"
+)
+
 // Error Messages
 const (
-	ErrMsgJokeNotFound     = "Joke not found"
-	ErrMsgJokeIDRequired   = "Joke ID is required"
-	ErrMsgFailedToRetrieve = "Failed to retrieve joke"
-	ErrMsgIDColumnNotFound = "id column not found"
-	ErrMsgNoJokesAvailable = "No jokes available in CSV file"
+	ErrMsgJokeNotFound         = "Joke not found"
+	ErrMsgJokeIDRequired       = "Joke ID is required"
+	ErrMsgFailedToRetrieve     = "Failed to retrieve joke"
+	ErrMsgIDColumnNotFound     = "id column not found"
+	ErrMsgNoJokesAvailable     = "No jokes available in data source"
+	ErrMsgCategoryNotFound     = "Category not found"
+	ErrMsgSearchRequired       = "Search query is required"
+	ErrMsgInvalidMatchMode     = "Invalid match mode, expected 'all' or 'any'"
+	ErrMsgInvalidPaging        = "Invalid pagination parameters"
+	ErrMsgInvalidCursor        = "Invalid cursor"
+	ErrMsgInvalidCount         = "Invalid count, expected a positive integer"
+	ErrMsgInvalidBatch         = "Invalid batch request, expected a non-empty ids list"
+	ErrMsgBatchTooLarge        = "Too many ids in batch request"
+	ErrMsgInvalidTimezone      = "Invalid timezone, expected an IANA name such as Europe/Paris, or UTC"
+	ErrMsgInvalidDate          = "Invalid date, expected YYYY-MM-DD"
+	ErrMsgFutureDate           = "Date cannot be in the future"
+	ErrMsgInvalidSeed          = "Invalid seed, expected at most 128 characters"
+	ErrMsgInvalidSession       = "Invalid session ID, expected at most 256 characters"
+	ErrMsgUnknownSource        = "unknown jokes source"
+	ErrMsgInvalidTable         = "invalid SQLite table name"
+	ErrMsgSourceReadOnly       = "Jokes source is read-only"
+	ErrMsgInvalidJoke          = "Invalid joke"
+	ErrMsgInvalidImport        = "Invalid import request, expected a non-empty jokes list"
+	ErrMsgJokeExists           = "Joke ID already exists"
+	ErrMsgLastJoke             = "The last joke cannot be deleted"
+	ErrMsgImportTooLarge       = "Too many jokes in import request"
+	ErrMsgFailedToPersist      = "Failed to persist jokes"
+	ErrMsgSourceHasSkippedRows = "Jokes source has duplicate or incomplete rows; fix them before writing"
+	ErrMsgUnauthorized         = "Unauthorized"
+	ErrMsgAPIKeyRequired       = "API key required"
+	ErrMsgInvalidAPIKey        = "Invalid API key"
+	ErrMsgRouteForbidden       = "API key not allowed for this route"
+	ErrMsgAdminForbidden       = "API key not granted the admin routes"
+	ErrMsgAuthRequired         = "Authentication required"
+	ErrMsgInvalidToken         = "Invalid token"
+	ErrMsgInsufficientScope    = "Token is missing the required scope"
+	ErrMsgTooManyRequests      = "Too many requests"
+	ErrMsgNotAcceptable        = "None of the accepted response formats is supported"
+	ErrMsgInternal             = "An unexpected error occurred"
+	ErrMsgRouteNotFound        = "No route matches the request"
 )
 
 // JSON Response Keys
 const (
-	JSONKeyError = "error"
-	JSONKeyID    = "id"
+	JSONKeyError       = "error"
+	JSONKeyID          = "id"
+	JSONKeyCategory    = "category"
+	JSONKeyMaxIDs      = "max_ids"
+	JSONKeySeed        = "seed"
+	JSONKeyData        = "data"
+	JSONKeyReason      = "reason"
+	JSONKeyIDs         = "ids"
+	JSONKeyMaxJokes    = "max_jokes"
+	JSONKeyScope       = "scope"
+	JSONKeyRetryAfter  = "retry_after"
+	JSONKeySupported   = "supported"
+	JSONKeyRequestID   = "request_id"
+	JSONKeySkippedRows = "skipped_rows"
 )
@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"jokes-provider/config"
 	"jokes-provider/middleware"
+	"jokes-provider/tracing"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// WriteCacheIfAllowed writes data to cache if caching is enabled and allowed by headers
-func WriteCacheIfAllowed(c *fiber.Ctx, cacheKey string, data map[string]string) error {
+// WriteCacheIfAllowed writes data to cache as JSON if caching is enabled and allowed by headers
+func WriteCacheIfAllowed[T any](c *fiber.Ctx, cacheKey string, data T) error {
 	if !config.CacheConfig.CacheEnabled {
 		config.LogInfo(c, "Skipping cache WRITE - caching disabled", "cache_key", cacheKey)
 		return nil
@@ -33,33 +34,61 @@ func WriteCacheIfAllowed(c *fiber.Ctx, cacheKey string, data map[string]string)
 	return nil
 }
 
-// ReadCacheIfAllowed reads data from cache if caching is enabled and allowed by headers
+// ReadCacheIfAllowed reads data written by WriteCacheIfAllowed from cache if
+// caching is enabled and allowed by headers
 // Returns (data, cacheHit) - cacheHit is true if data was found in cache
-func ReadCacheIfAllowed(c *fiber.Ctx, cacheKey string) (map[string]string, bool) {
+func ReadCacheIfAllowed[T any](c *fiber.Ctx, cacheKey string) (T, bool) {
+	var result T
+
+	span := tracing.Start(c, "cache.read", tracing.AttributeCacheKey.String(cacheKey))
+	defer span.End()
+
 	if !config.CacheConfig.CacheEnabled {
+		span.SetAttributes(tracing.AttributeCacheSkip.String("disabled"))
 		config.LogInfo(c, "Skipping cache READ - caching disabled", "cache_key", cacheKey)
-		return nil, false
+		return result, false
 	}
 
 	if shouldSkipCache(c) {
+		span.SetAttributes(tracing.AttributeCacheSkip.String("no-cache"))
 		config.LogInfo(c, "Skipping cache READ - Cache-Control: no-cache", "cache_key", cacheKey)
-		return nil, false
+		return result, false
 	}
 
 	cachedData, err := middleware.GetFromCache(c, cacheKey)
 	if err != nil {
-		return nil, false
+		span.Fail(err)
+		return result, false
 	}
 
 	if cachedData == nil {
-		return nil, false
+		span.SetAttributes(tracing.AttributeCacheHit.Bool(false))
+		return result, false
 	}
 
-	var result map[string]string
 	if err := json.Unmarshal(cachedData, &result); err != nil {
+		span.Fail(err)
 		config.LogError(c, "Error unmarshaling cached data", "cache_key", cacheKey, "error", err.Error())
-		return nil, false
+		var zero T
+		return zero, false
 	}
 
+	span.SetAttributes(tracing.AttributeCacheHit.Bool(true))
 	return result, true
 }
+
+// InvalidateCache deletes cache entries if caching is enabled, regardless of Cache-Control
+func InvalidateCache(c *fiber.Ctx, cacheKeys ...string) error {
+	if !config.CacheConfig.CacheEnabled {
+		return nil
+	}
+
+	var firstErr error
+	for _, cacheKey := range cacheKeys {
+		if err := middleware.DeleteFromCache(c, cacheKey); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}